@@ -0,0 +1,409 @@
+package rgbmatrix
+
+import (
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+)
+
+// NewImageAnimation returns an Animation showing the given image during the
+// given delay
+func NewImageAnimation(img image.Image, delay time.Duration) Animation {
+	return &imageAnimation{img: img, delay: delay}
+}
+
+type imageAnimation struct {
+	Clocked
+	img    image.Image
+	delay  time.Duration
+	played bool
+}
+
+func (a *imageAnimation) Next() (image.Image, <-chan time.Time, error) {
+	if a.played {
+		return nil, nil, io.EOF
+	}
+
+	a.played = true
+	return a.img, a.Clock().After(a.delay), nil
+}
+
+func (a *imageAnimation) Reset() {
+	a.played = false
+}
+
+// NewGIFAnimation returns an Animation playing the frames of the given GIF,
+// using its delays, disposal methods and loop count
+func NewGIFAnimation(g *gif.GIF) Animation {
+	w, h := g.Config.Width, g.Config.Height
+	if w == 0 || h == 0 {
+		for _, frame := range g.Image {
+			r := image.Rect(0, 0, w, h).Union(frame.Bounds())
+			w, h = r.Max.X, r.Max.Y
+		}
+	}
+
+	return &gifAnimation{g: g, canvas: image.NewRGBA(image.Rect(0, 0, w, h))}
+}
+
+type gifAnimation struct {
+	Clocked
+	g        *gif.GIF
+	canvas   *image.RGBA
+	previous *image.RGBA
+	frame    int
+	loop     int
+}
+
+func (a *gifAnimation) Next() (image.Image, <-chan time.Time, error) {
+	if len(a.g.Image) == 0 {
+		return nil, nil, io.EOF
+	}
+
+	if a.frame == len(a.g.Image) {
+		a.loop++
+		if a.g.LoopCount < 0 || (a.g.LoopCount > 0 && a.loop > a.g.LoopCount) {
+			return nil, nil, io.EOF
+		}
+
+		a.frame = 0
+		draw.Draw(a.canvas, a.canvas.Bounds(), image.Transparent, image.ZP, draw.Src)
+	}
+
+	i := a.frame
+	a.frame++
+	a.dispose(i - 1)
+
+	img := a.g.Image[i]
+	if a.disposal(i) == gif.DisposalPrevious {
+		a.previous = image.NewRGBA(a.canvas.Bounds())
+		copy(a.previous.Pix, a.canvas.Pix)
+	}
+
+	draw.Draw(a.canvas, img.Bounds(), img, img.Bounds().Min, draw.Over)
+
+	var delay time.Duration
+	if i < len(a.g.Delay) {
+		delay = time.Millisecond * time.Duration(a.g.Delay[i]) * 10
+	}
+
+	return a.canvas, a.Clock().After(delay), nil
+}
+
+func (a *gifAnimation) Reset() {
+	a.frame, a.loop, a.previous = 0, 0, nil
+	draw.Draw(a.canvas, a.canvas.Bounds(), image.Transparent, image.ZP, draw.Src)
+}
+
+// dispose applies the disposal method of the frame i to the canvas
+func (a *gifAnimation) dispose(i int) {
+	if i < 0 {
+		return
+	}
+
+	switch a.disposal(i) {
+	case gif.DisposalBackground:
+		draw.Draw(a.canvas, a.g.Image[i].Bounds(), image.Transparent, image.ZP, draw.Src)
+	case gif.DisposalPrevious:
+		if a.previous != nil {
+			copy(a.canvas.Pix, a.previous.Pix)
+		}
+	}
+}
+
+func (a *gifAnimation) disposal(i int) byte {
+	if i < len(a.g.Disposal) {
+		return a.g.Disposal[i]
+	}
+
+	return gif.DisposalNone
+}
+
+// Resetter is implemented by the Animations that can be played again from the
+// start, after returning io.EOF. The Animations returned by NewImageAnimation,
+// NewGIFAnimation and NewTransitionAnimation, and by the functions wrapping
+// Animations, as Sequence, Loop, Timeout, Concat, Map or Limit, implement it,
+// resetting the Animations they wrap that implement it.
+type Resetter interface {
+	Reset()
+}
+
+// reset resets a if it implements Resetter, returns false otherwise
+func reset(a Animation) bool {
+	r, ok := a.(Resetter)
+	if ok {
+		r.Reset()
+	}
+
+	return ok
+}
+
+// Sequence returns an Animation playing the given animations one after the
+// other, until every one of them returns io.EOF
+func Sequence(animations ...Animation) Animation {
+	return &sequenceAnimation{animations: animations}
+}
+
+type sequenceAnimation struct {
+	animations []Animation
+	current    int
+}
+
+func (a *sequenceAnimation) Next() (image.Image, <-chan time.Time, error) {
+	for a.current < len(a.animations) {
+		i, n, err := a.animations[a.current].Next()
+		if err != io.EOF {
+			return i, n, err
+		}
+
+		a.current++
+	}
+
+	return nil, nil, io.EOF
+}
+
+func (a *sequenceAnimation) Reset() {
+	for _, anim := range a.animations {
+		reset(anim)
+	}
+
+	a.current = 0
+}
+
+func (a *sequenceAnimation) SetClock(c Clock) {
+	for _, anim := range a.animations {
+		SetClock(anim, c)
+	}
+}
+
+// Loop returns an Animation playing the given animation n times, or forever if
+// n is zero or negative, as Repeat. The animation should implement Resetter to
+// be played more than once.
+func Loop(a Animation, n int) Animation {
+	return &loopAnimation{a: a, n: n}
+}
+
+// Repeat returns an Animation playing the given animation forever, the
+// animation should implement Resetter to be played more than once
+func Repeat(a Animation) Animation {
+	return &loopAnimation{a: a}
+}
+
+type loopAnimation struct {
+	a Animation
+	// n is the number of loops, zero or negative is forever
+	n    int
+	loop int
+}
+
+func (a *loopAnimation) Next() (image.Image, <-chan time.Time, error) {
+	i, n, err := a.a.Next()
+	if err != io.EOF {
+		return i, n, err
+	}
+
+	a.loop++
+	if (a.n > 0 && a.loop >= a.n) || !reset(a.a) {
+		return nil, nil, io.EOF
+	}
+
+	// an animation without frames ends here, instead of looping forever
+	return a.a.Next()
+}
+
+func (a *loopAnimation) Reset() {
+	reset(a.a)
+	a.loop = 0
+}
+
+func (a *loopAnimation) SetClock(c Clock) {
+	SetClock(a.a, c)
+}
+
+// Timeout returns an Animation playing the given animation until it ends or
+// during the given duration, since the first frame, whatever comes first
+func Timeout(a Animation, d time.Duration) Animation {
+	return &timeoutAnimation{a: a, d: d}
+}
+
+type timeoutAnimation struct {
+	Clocked
+	a        Animation
+	d        time.Duration
+	deadline time.Time
+	frame    frameWait
+}
+
+func (a *timeoutAnimation) Next() (image.Image, <-chan time.Time, error) {
+	a.frame.stop()
+
+	clock := a.Clock()
+	if a.deadline.IsZero() {
+		a.deadline = clock.Now().Add(a.d)
+	}
+
+	remaining := a.deadline.Sub(clock.Now())
+	if remaining <= 0 {
+		return nil, nil, io.EOF
+	}
+
+	i, n, err := a.a.Next()
+	if err != nil {
+		return i, n, err
+	}
+
+	timer, cancel := a.frame.start(clock, remaining)
+	wait := make(chan time.Time, 1)
+	go func() {
+		select {
+		case t := <-n:
+			wait <- t
+		case t := <-timer.C():
+			wait <- t
+		case <-cancel:
+		}
+	}()
+
+	return i, wait, nil
+}
+
+func (a *timeoutAnimation) Reset() {
+	a.frame.stop()
+	reset(a.a)
+	a.deadline = time.Time{}
+}
+
+func (a *timeoutAnimation) SetClock(c Clock) {
+	a.Clocked.SetClock(c)
+	SetClock(a.a, c)
+}
+
+// Concat returns an Animation playing the given animations one after the
+// other, using the transition t between the last frame of every animation
+// and the first frames of the next one
+func Concat(t *Transition, animations ...Animation) Animation {
+	if len(animations) < 2 {
+		return Sequence(animations...)
+	}
+
+	return NewTransitionAnimation(animations[0], Concat(t, animations[1:]...), t)
+}
+
+// Map returns an Animation applying the function f to every frame of the
+// given animation
+func Map(a Animation, f func(image.Image) image.Image) Animation {
+	return &mapAnimation{a: a, f: f}
+}
+
+type mapAnimation struct {
+	a Animation
+	f func(image.Image) image.Image
+}
+
+func (a *mapAnimation) Next() (image.Image, <-chan time.Time, error) {
+	i, n, err := a.a.Next()
+	if err != nil {
+		return i, n, err
+	}
+
+	return a.f(i), n, nil
+}
+
+func (a *mapAnimation) Reset() {
+	reset(a.a)
+}
+
+func (a *mapAnimation) SetClock(c Clock) {
+	SetClock(a.a, c)
+}
+
+// Limit returns an Animation playing the given animation at most at the given
+// frames per second, the frames are shown at least during 1/fps seconds
+func Limit(a Animation, fps int) Animation {
+	if fps <= 0 {
+		return a
+	}
+
+	return &limitAnimation{a: a, interval: time.Second / time.Duration(fps)}
+}
+
+type limitAnimation struct {
+	Clocked
+	a        Animation
+	interval time.Duration
+	frame    frameWait
+}
+
+func (a *limitAnimation) Next() (image.Image, <-chan time.Time, error) {
+	a.frame.stop()
+
+	clock := a.Clock()
+	min := clock.Now().Add(a.interval)
+	i, n, err := a.a.Next()
+	if err != nil || n == nil {
+		return i, n, err
+	}
+
+	timer, cancel := a.frame.start(clock, min.Sub(clock.Now()))
+	wait := make(chan time.Time, 1)
+	go func() {
+		var t time.Time
+		select {
+		case t = <-n:
+		case <-cancel:
+			return
+		}
+
+		if clock.Now().Before(min) {
+			select {
+			case t = <-timer.C():
+			case <-cancel:
+				return
+			}
+		}
+
+		wait <- t
+	}()
+
+	return i, wait, nil
+}
+
+func (a *limitAnimation) Reset() {
+	a.frame.stop()
+	reset(a.a)
+}
+
+func (a *limitAnimation) SetClock(c Clock) {
+	a.Clocked.SetClock(c)
+	SetClock(a.a, c)
+}
+
+// frameWait is the wait of the last frame of an Animation wrapping another
+// one, the goroutine forwarding the chan of the wrapped frame is cancelled
+// and its timer stopped once the next frame is requested, so none of them
+// outlives the frame
+type frameWait struct {
+	timer  Timer
+	cancel chan struct{}
+}
+
+// start stops the wait of the previous frame, and returns a new timer
+// expiring after d and a chan closed once the frame is stopped
+func (w *frameWait) start(clock Clock, d time.Duration) (Timer, <-chan struct{}) {
+	w.stop()
+	w.timer, w.cancel = clock.NewTimer(d), make(chan struct{})
+	return w.timer, w.cancel
+}
+
+// stop stops the wait of the last frame, if any
+func (w *frameWait) stop() {
+	if w.cancel == nil {
+		return
+	}
+
+	w.timer.Stop()
+	close(w.cancel)
+	w.timer, w.cancel = nil, nil
+}
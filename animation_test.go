@@ -0,0 +1,141 @@
+package rgbmatrix
+
+import (
+	"image"
+	"image/color"
+	"io"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type AnimationSuite struct{}
+
+var _ = Suite(&AnimationSuite{})
+
+// frames plays a until io.EOF or max frames, returning the color of the
+// first pixel of every frame
+func frames(c *C, a Animation, max int) []color.Color {
+	var colors []color.Color
+	for len(colors) < max {
+		i, n, err := a.Next()
+		if err == io.EOF {
+			break
+		}
+
+		c.Assert(err, IsNil)
+		colors = append(colors, color.RGBAModel.Convert(i.At(0, 0)))
+		<-n
+	}
+
+	return colors
+}
+
+func (s *AnimationSuite) TestSequence(c *C) {
+	a := Sequence(
+		NewImageAnimation(newUniformRGBA(2, 2, red), 0),
+		Sequence(),
+		NewImageAnimation(newUniformRGBA(2, 2, blue), 0),
+	)
+
+	c.Assert(frames(c, a, 10), DeepEquals, []color.Color{red, blue})
+}
+
+func (s *AnimationSuite) TestLoop(c *C) {
+	a := Loop(Sequence(
+		NewImageAnimation(newUniformRGBA(2, 2, red), 0),
+		NewImageAnimation(newUniformRGBA(2, 2, blue), 0),
+	), 2)
+
+	c.Assert(frames(c, a, 10), DeepEquals, []color.Color{red, blue, red, blue})
+
+	a.(Resetter).Reset()
+	c.Assert(frames(c, a, 10), HasLen, 4)
+}
+
+func (s *AnimationSuite) TestRepeat(c *C) {
+	a := Repeat(NewImageAnimation(newUniformRGBA(2, 2, red), 0))
+	c.Assert(frames(c, a, 5), HasLen, 5)
+
+	c.Assert(frames(c, Repeat(Sequence()), 5), HasLen, 0)
+}
+
+func (s *AnimationSuite) TestTimeout(c *C) {
+	a := Timeout(NewImageAnimation(newUniformRGBA(2, 2, red), time.Hour), 20*time.Millisecond)
+
+	start := time.Now()
+	c.Assert(frames(c, a, 10), DeepEquals, []color.Color{red})
+	c.Assert(time.Since(start) < time.Second, Equals, true)
+}
+
+func (s *AnimationSuite) TestTimeoutStopsFrameWait(c *C) {
+	clock := NewFakeClock(epoch)
+	a := Timeout(Repeat(NewImageAnimation(newUniformRGBA(2, 2, red), time.Hour)), time.Minute)
+	SetClock(a, clock)
+
+	// every frame waits for the image and the timeout
+	_, _, err := a.Next()
+	c.Assert(err, IsNil)
+	c.Assert(clock.Waiters(), Equals, 2)
+
+	// the timeout of the previous frame is stopped
+	_, _, err = a.Next()
+	c.Assert(err, IsNil)
+	c.Assert(clock.Waiters(), Equals, 3)
+
+	a.(Resetter).Reset()
+	c.Assert(clock.Waiters(), Equals, 2)
+}
+
+func (s *AnimationSuite) TestConcat(c *C) {
+	a := Concat(&Transition{Effect: Crossfade, Duration: 0},
+		NewImageAnimation(newUniformRGBA(2, 2, red), 0),
+		NewImageAnimation(newUniformRGBA(2, 2, blue), 0),
+	)
+
+	colors := frames(c, a, 10)
+	c.Assert(colors[0], Equals, red)
+	c.Assert(colors[len(colors)-1], Equals, blue)
+}
+
+func (s *AnimationSuite) TestConcatSingle(c *C) {
+	// the single animation is wrapped, even if it can't be reset
+	a := Concat(nil, &stillAnimation{img: newUniformRGBA(2, 2, red)})
+	_, ok := a.(Resetter)
+	c.Assert(ok, Equals, true)
+}
+
+func (s *AnimationSuite) TestMap(c *C) {
+	a := Map(NewImageAnimation(newUniformRGBA(2, 2, red), 0), func(image.Image) image.Image {
+		return newUniformRGBA(2, 2, blue)
+	})
+
+	c.Assert(frames(c, a, 10), DeepEquals, []color.Color{blue})
+}
+
+func (s *AnimationSuite) TestLimit(c *C) {
+	a := Limit(Repeat(NewImageAnimation(newUniformRGBA(2, 2, red), 0)), 50)
+
+	start := time.Now()
+	c.Assert(frames(c, a, 3), HasLen, 3)
+	c.Assert(time.Since(start) >= 60*time.Millisecond, Equals, true)
+}
+
+func (s *AnimationSuite) TestLimitFakeClock(c *C) {
+	clock := NewFakeClock(epoch)
+	a := Limit(Repeat(NewImageAnimation(newUniformRGBA(2, 2, red), 0)), 50)
+	SetClock(a, clock)
+
+	_, n, err := a.Next()
+	c.Assert(err, IsNil)
+
+	clock.Advance(20*time.Millisecond - time.Nanosecond)
+	select {
+	case <-n:
+		c.Fatal("frame shown less than the interval")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	clock.Advance(time.Nanosecond)
+	c.Assert(<-n, Equals, epoch.Add(20*time.Millisecond))
+}
@@ -0,0 +1,229 @@
+package rgbmatrix
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix/emulator"
+	"github.com/mcuadros/go-rpi-rgb-led-matrix/terminal"
+)
+
+// Backend creates a Matrix for NewMatrix, addr is the part of the URI between
+// the scheme and the query, and params the parameters of the query
+type Backend func(addr string, params url.Values, config *HardwareConfig) (Matrix, error)
+
+var (
+	backendsMu sync.Mutex
+	backends   = make(map[string]Backend)
+)
+
+// RegisterBackend makes available a Backend to NewMatrix with the given
+// scheme. If RegisterBackend is called twice with the same scheme or if the
+// backend is nil, it panics.
+func RegisterBackend(scheme string, b Backend) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+
+	if b == nil {
+		panic("rgbmatrix: RegisterBackend backend is nil")
+	}
+
+	if _, dup := backends[scheme]; dup {
+		panic("rgbmatrix: RegisterBackend called twice for backend " + scheme)
+	}
+
+	backends[scheme] = b
+}
+
+// Backends returns a sorted list of the schemes of the registered backends
+func Backends() []string {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+
+	var list []string
+	for scheme := range backends {
+		list = append(list, scheme)
+	}
+
+	sort.Strings(list)
+	return list
+}
+
+// NewMatrix returns a new Matrix created by the backend of the scheme of the
+// given URI, with the given config, DefaultConfig if nil. The backends
+// available by default are:
+//
+//	hw://                           the real matrix
+//	emulator://?pitch=12&round=1&glow=4
+//	                                a window emulating the matrix
+//	headless://out.gif?pitch=12     an emulator without window, saving the
+//	                                frames on Close as an animated GIF, a
+//	                                contact sheet if .png, or a PNG per frame
+//	                                if the name has a verb, as frame-%04d.png
+//	terminal://?mode=sixel          draws the matrix in the terminal, mode can
+//	                                be halfblocks, sixel or kitty
+//
+// The rpc and web packages register the rpc://host:port and web://host:port
+// backends, they are available once imported:
+//
+//	import _ "github.com/mcuadros/go-rpi-rgb-led-matrix/rpc"
+func NewMatrix(uri string, config *HardwareConfig) (m Matrix, err error) {
+	scheme, rest, ok := strings.Cut(uri, "://")
+	if !ok {
+		return nil, fmt.Errorf("invalid matrix URI %q, expected scheme://", uri)
+	}
+
+	backendsMu.Lock()
+	b, ok := backends[scheme]
+	backendsMu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown matrix backend %q, available: %s",
+			scheme, strings.Join(Backends(), ", "),
+		)
+	}
+
+	addr, query, _ := strings.Cut(rest, "?")
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid matrix URI %q: %s", uri, err)
+	}
+
+	if config == nil {
+		c := DefaultConfig
+		config = &c
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			err, ok = r.(error)
+			if !ok {
+				err = fmt.Errorf("error creating matrix: %v", r)
+			}
+		}
+	}()
+
+	return b(addr, params, config)
+}
+
+func init() {
+	RegisterBackend("emulator", newEmulatorBackend)
+	RegisterBackend("headless", newHeadlessBackend)
+	RegisterBackend("terminal", newTerminalBackend)
+}
+
+func newEmulatorBackend(_ string, params url.Values, config *HardwareConfig) (Matrix, error) {
+	pitch, err := intParam(params, "pitch", emulator.DefaultPixelPitch)
+	if err != nil {
+		return nil, err
+	}
+
+	glow, err := intParam(params, "glow", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	round, err := boolParam(params, "round")
+	if err != nil {
+		return nil, err
+	}
+
+	w, h := config.geometry()
+	e := emulator.NewEmulator(w, h, pitch, false)
+	e.Panel = config.panel()
+	e.Topology = config.topology()
+	e.RoundLEDs, e.Glow = round, glow
+	e.Init()
+	return e, nil
+}
+
+func newHeadlessBackend(output string, params url.Values, config *HardwareConfig) (Matrix, error) {
+	pitch, err := intParam(params, "pitch", emulator.DefaultPixelPitch)
+	if err != nil {
+		return nil, err
+	}
+
+	w, h := config.geometry()
+	e := emulator.NewHeadless(w, h, pitch)
+	e.Output = output
+	e.Panel = config.panel()
+	return e, nil
+}
+
+func newTerminalBackend(_ string, params url.Values, config *HardwareConfig) (Matrix, error) {
+	w, h := config.geometry()
+	t := terminal.NewTerminal(w, h)
+	switch mode := params.Get("mode"); mode {
+	case "", "auto":
+	case "halfblocks":
+		t.Mode = terminal.HalfBlocks
+	case "sixel":
+		t.Mode = terminal.Sixel
+	case "kitty":
+		t.Mode = terminal.Kitty
+	default:
+		return nil, fmt.Errorf("invalid terminal mode %q", mode)
+	}
+
+	return t, nil
+}
+
+// topology returns the emulator.Topology of the panels with this config
+func (c *HardwareConfig) topology() *emulator.Topology {
+	return &emulator.Topology{
+		Rows:        c.Rows,
+		Cols:        c.Cols,
+		ChainLength: c.ChainLength,
+		Parallel:    c.Parallel,
+		Interlaced:  c.ScanMode == Interlaced,
+	}
+}
+
+// panel returns an emulator.Panel showing the colors as a panel with this
+// config does
+func (c *HardwareConfig) panel() *emulator.Panel {
+	p := emulator.NewPanel()
+	p.InverseColors = c.InverseColors
+	if c.PWMBits != 0 {
+		p.PWMBits = c.PWMBits
+	}
+
+	if c.Brightness != 0 {
+		p.Brightness = c.Brightness
+	}
+
+	return p
+}
+
+func intParam(params url.Values, name string, def int) (int, error) {
+	v := params.Get(name)
+	if v == "" {
+		return def, nil
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s parameter %q", name, v)
+	}
+
+	return i, nil
+}
+
+func boolParam(params url.Values, name string) (bool, error) {
+	v := params.Get(name)
+	if v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s parameter %q", name, v)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,95 @@
+package rgbmatrix
+
+import (
+	"image/color"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix/emulator"
+	"github.com/mcuadros/go-rpi-rgb-led-matrix/terminal"
+	. "gopkg.in/check.v1"
+)
+
+type BackendSuite struct{}
+
+var _ = Suite(&BackendSuite{})
+
+func (s *BackendSuite) TestBackends(c *C) {
+	c.Assert(Backends(), DeepEquals, []string{"emulator", "headless", "hw", "terminal"})
+}
+
+func (s *BackendSuite) TestRegisterBackend(c *C) {
+	var addr string
+	var params url.Values
+	RegisterBackend("test", func(a string, p url.Values, config *HardwareConfig) (Matrix, error) {
+		addr, params = a, p
+		return NewMatrixMock(), nil
+	})
+	defer func() { delete(backends, "test") }()
+
+	m, err := NewMatrix("test://foo:1234?bar=qux", nil)
+	c.Assert(err, IsNil)
+	c.Assert(m, NotNil)
+	c.Assert(addr, Equals, "foo:1234")
+	c.Assert(params.Get("bar"), Equals, "qux")
+
+	c.Assert(func() { RegisterBackend("test", nil) }, PanicMatches, ".*nil")
+	c.Assert(func() {
+		RegisterBackend("test", func(string, url.Values, *HardwareConfig) (Matrix, error) { return nil, nil })
+	}, PanicMatches, ".*twice.*")
+}
+
+func (s *BackendSuite) TestNewMatrixInvalid(c *C) {
+	_, err := NewMatrix("foo", nil)
+	c.Assert(err, ErrorMatches, `invalid matrix URI "foo".*`)
+
+	_, err = NewMatrix("foo://", nil)
+	c.Assert(err, ErrorMatches, `unknown matrix backend "foo", available: emulator, headless, hw, terminal`)
+
+	_, err = NewMatrix("terminal://?mode=foo", nil)
+	c.Assert(err, ErrorMatches, `invalid terminal mode "foo"`)
+
+	_, err = NewMatrix("headless://?pitch=foo", nil)
+	c.Assert(err, ErrorMatches, `invalid pitch parameter "foo"`)
+}
+
+func (s *BackendSuite) TestNewMatrixRecover(c *C) {
+	RegisterBackend("panic", func(string, url.Values, *HardwareConfig) (Matrix, error) {
+		panic("foo")
+	})
+	defer func() { delete(backends, "panic") }()
+
+	_, err := NewMatrix("panic://", nil)
+	c.Assert(err, ErrorMatches, "error creating matrix: foo")
+}
+
+func (s *BackendSuite) TestNewMatrixHeadless(c *C) {
+	config := DefaultConfig
+	config.Cols, config.ChainLength = 64, 2
+	path := filepath.Join(c.MkDir(), "out.gif")
+
+	m, err := NewMatrix("headless://"+path+"?pitch=4", &config)
+	c.Assert(err, IsNil)
+
+	h, ok := m.(*emulator.Headless)
+	c.Assert(ok, Equals, true)
+	c.Assert(h.PixelPitch, Equals, 4)
+
+	w, height := m.Geometry()
+	c.Assert(w, Equals, 128)
+	c.Assert(height, Equals, 32)
+
+	m.Set(0, color.White)
+	c.Assert(m.Render(), IsNil)
+	c.Assert(m.Close(), IsNil)
+
+	_, err = os.Stat(path)
+	c.Assert(err, IsNil)
+}
+
+func (s *BackendSuite) TestNewMatrixTerminal(c *C) {
+	m, err := NewMatrix("terminal://?mode=kitty", nil)
+	c.Assert(err, IsNil)
+	c.Assert(m.(*terminal.Terminal).Mode, Equals, terminal.Kitty)
+}
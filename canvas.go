@@ -4,6 +4,7 @@ import (
 	"image"
 	"image/color"
 	"image/draw"
+	"sync"
 )
 
 // Canvas is a image.Image representation of a WS281x matrix, it implements
@@ -12,6 +13,9 @@ type Canvas struct {
 	w, h   int
 	m      Matrix
 	closed bool
+
+	zm    sync.Mutex
+	zones *zones
 }
 
 // NewCanvas returns a new Canvas using the given width and height and creates
@@ -0,0 +1,286 @@
+package rgbmatrix
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock provides the time to the ToolKit and the Animations of this package
+// and its subpackages, it allows to replace the system clock, for example by
+// a FakeClock in tests. The SystemClock is used where no Clock is given.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel
+	After(d time.Duration) <-chan time.Time
+	// Sleep pauses the current goroutine for at least the duration d
+	Sleep(d time.Duration)
+	// NewTimer creates a new Timer that will send the current time on its
+	// channel after at least duration d
+	NewTimer(d time.Duration) Timer
+	// AfterFunc waits for the duration to elapse and then calls f in its own
+	// goroutine, the channel of the returned Timer is nil
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a single event of a Clock, as time.Timer
+type Timer interface {
+	// C returns the channel on which the time is delivered
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing, returns false if the timer has
+	// already expired or been stopped
+	Stop() bool
+	// Reset changes the timer to expire after duration d, returns true if the
+	// timer had been active
+	Reset(d time.Duration) bool
+}
+
+// ClockSetter is implemented by the Animations depending on the time. The
+// ToolKit, the Compositor and the playlist Scheduler set their Clock to the
+// Animations they play, and the Animations wrapping others set it to them.
+type ClockSetter interface {
+	SetClock(c Clock)
+}
+
+// SetClock sets the Clock of a if it implements ClockSetter, nothing is done
+// if c is nil
+func SetClock(a Animation, c Clock) {
+	if s, ok := a.(ClockSetter); ok && c != nil {
+		s.SetClock(c)
+	}
+}
+
+// Clocked implements ClockSetter, it is embedded by the Animations depending
+// on the time, the SystemClock is used until SetClock is called
+type Clocked struct {
+	c Clock
+}
+
+// SetClock sets the Clock used by the Animation
+func (a *Clocked) SetClock(c Clock) {
+	a.c = c
+}
+
+// Clock returns the Clock used by the Animation
+func (a *Clocked) Clock() Clock {
+	return ClockOrSystem(a.c)
+}
+
+// ClockOrSystem returns c, or the SystemClock if c is nil
+func ClockOrSystem(c Clock) Clock {
+	if c == nil {
+		return SystemClock{}
+	}
+
+	return c
+}
+
+// SystemClock is a Clock using the functions of the time package
+type SystemClock struct{}
+
+// Now calls time.Now
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After calls time.After
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Sleep calls time.Sleep
+func (SystemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// NewTimer calls time.NewTimer
+func (SystemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+// AfterFunc calls time.AfterFunc
+func (SystemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return systemTimer{time.AfterFunc(d, f)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// FakeClock is a Clock that only moves when Advance is called, the timers,
+// sleeps and channels of After are fired by Advance when their time is
+// reached
+type FakeClock struct {
+	m       sync.Mutex
+	now     time.Time
+	timers  []*fakeTimer
+	changed chan struct{}
+}
+
+// NewFakeClock returns a new FakeClock starting at the given time
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now, changed: make(chan struct{})}
+}
+
+// Now returns the current time of the clock
+func (c *FakeClock) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.now
+}
+
+// After returns a channel receiving the time once the clock is advanced by d
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// Sleep blocks until the clock is advanced by d
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// NewTimer returns a Timer firing once the clock is advanced by d
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{c: c, ch: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// AfterFunc returns a Timer calling f once the clock is advanced by d
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	t := &fakeTimer{c: c, f: f}
+	t.Reset(d)
+	return t
+}
+
+// Advance moves the clock forward by d, firing in order the timers expiring in
+// the meantime. The functions of the timers created by AfterFunc are called
+// before Advance returns, so their effects can be checked right after.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.m.Lock()
+	end := c.now.Add(d)
+
+	var funcs []func()
+	for len(c.timers) > 0 && !c.timers[0].when.After(end) {
+		t := c.timers[0]
+		c.timers = c.timers[1:]
+		c.now = t.when
+		if t.f != nil {
+			funcs = append(funcs, t.f)
+			continue
+		}
+
+		t.fire()
+	}
+
+	c.now = end
+	c.m.Unlock()
+
+	for _, f := range funcs {
+		f()
+	}
+}
+
+// Waiters returns the number of timers waiting to fire
+func (c *FakeClock) Waiters() int {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return len(c.timers)
+}
+
+// BlockUntil blocks until at least n timers are waiting to fire, useful to
+// advance the clock once the code under test is waiting for it
+func (c *FakeClock) BlockUntil(n int) {
+	for {
+		c.m.Lock()
+		waiters, changed := len(c.timers), c.changed
+		c.m.Unlock()
+
+		if waiters >= n {
+			return
+		}
+
+		<-changed
+	}
+}
+
+// add adds t to the pending timers, the lock should be held
+func (c *FakeClock) add(t *fakeTimer) {
+	if !t.when.After(c.now) {
+		t.fire()
+		return
+	}
+
+	i := sort.Search(len(c.timers), func(i int) bool {
+		return c.timers[i].when.After(t.when)
+	})
+
+	c.timers = append(c.timers, nil)
+	copy(c.timers[i+1:], c.timers[i:])
+	c.timers[i] = t
+
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// remove removes t from the pending timers, the lock should be held
+func (c *FakeClock) remove(t *fakeTimer) bool {
+	for i, pending := range c.timers {
+		if pending == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+type fakeTimer struct {
+	c    *FakeClock
+	when time.Time
+	ch   chan time.Time
+	f    func()
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.c.m.Lock()
+	defer t.c.m.Unlock()
+
+	return t.c.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.c.m.Lock()
+	defer t.c.m.Unlock()
+
+	active := t.c.remove(t)
+	t.when = t.c.now.Add(d)
+	t.c.add(t)
+
+	return active
+}
+
+// fire delivers the time of the clock, the lock should be held
+func (t *fakeTimer) fire() {
+	if t.f != nil {
+		go t.f()
+		return
+	}
+
+	select {
+	case t.ch <- t.c.now:
+	default:
+	}
+}
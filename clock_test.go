@@ -0,0 +1,112 @@
+package rgbmatrix
+
+import (
+	"image/color"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type ClockSuite struct{}
+
+var _ = Suite(&ClockSuite{})
+
+var epoch = time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func (s *ClockSuite) TestFakeClockAfter(c *C) {
+	clock := NewFakeClock(epoch)
+	a := clock.After(time.Second)
+	b := clock.After(2 * time.Second)
+	c.Assert(clock.Waiters(), Equals, 2)
+
+	clock.Advance(1500 * time.Millisecond)
+	c.Assert(<-a, Equals, epoch.Add(time.Second))
+	c.Assert(clock.Now(), Equals, epoch.Add(1500*time.Millisecond))
+
+	select {
+	case <-b:
+		c.Fatal("fired before its time")
+	default:
+	}
+
+	clock.Advance(time.Second)
+	c.Assert(<-b, Equals, epoch.Add(2*time.Second))
+	c.Assert(clock.Waiters(), Equals, 0)
+}
+
+func (s *ClockSuite) TestFakeClockTimer(c *C) {
+	clock := NewFakeClock(epoch)
+	t := clock.NewTimer(time.Second)
+	c.Assert(t.Stop(), Equals, true)
+	c.Assert(t.Stop(), Equals, false)
+
+	c.Assert(t.Reset(time.Second), Equals, false)
+	clock.Advance(time.Second)
+	c.Assert(<-t.C(), Equals, epoch.Add(time.Second))
+
+	fired := make(chan bool)
+	clock.AfterFunc(0, func() { fired <- true })
+	c.Assert(<-fired, Equals, true)
+}
+
+func (s *ClockSuite) TestFakeClockSleep(c *C) {
+	clock := NewFakeClock(epoch)
+	done := make(chan time.Time)
+	go func() {
+		clock.Sleep(time.Minute)
+		done <- clock.Now()
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	c.Assert(<-done, Equals, epoch.Add(time.Minute))
+}
+
+func (s *ClockSuite) TestFakeClockAdvanceAfterFunc(c *C) {
+	clock := NewFakeClock(epoch)
+
+	var fired []time.Duration
+	clock.AfterFunc(2*time.Second, func() { fired = append(fired, 2*time.Second) })
+	clock.AfterFunc(time.Second, func() { fired = append(fired, time.Second) })
+
+	clock.Advance(3 * time.Second)
+	c.Assert(fired, DeepEquals, []time.Duration{time.Second, 2 * time.Second})
+}
+
+func (s *ClockSuite) TestPlayImageFakeClock(c *C) {
+	clock := NewFakeClock(epoch)
+	tk, m := newRecorderToolKit()
+	tk.Clock = clock
+
+	done := make(chan error)
+	go func() {
+		done <- tk.PlayImage(newUniformRGBA(10, 20, red), time.Hour)
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	c.Assert(<-done, IsNil)
+	c.Assert(m.frames, DeepEquals, []color.Color{red})
+}
+
+func (s *ClockSuite) TestPlayAnimationSetClock(c *C) {
+	clock := NewFakeClock(epoch)
+	tk, m := newRecorderToolKit()
+	tk.Clock = clock
+
+	done := make(chan error)
+	go func() {
+		done <- tk.PlayAnimation(Sequence(
+			NewImageAnimation(newUniformRGBA(10, 20, red), time.Hour),
+			NewImageAnimation(newUniformRGBA(10, 20, blue), time.Hour),
+		))
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+
+	c.Assert(<-done, IsNil)
+	c.Assert(m.frames, DeepEquals, []color.Color{red, blue})
+}
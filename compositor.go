@@ -0,0 +1,449 @@
+package rgbmatrix
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"sort"
+	"sync"
+)
+
+// BlendMode defines how a Layer is combined with the layers below it
+type BlendMode int
+
+const (
+	// BlendNormal draws the layer over the layers below
+	BlendNormal BlendMode = iota
+	// BlendAdd adds the colors of the layer to the layers below
+	BlendAdd
+	// BlendMultiply multiplies the colors of the layer and the layers below,
+	// the result is always darker
+	BlendMultiply
+	// BlendScreen inverts, multiplies and inverts again the colors, the result
+	// is always lighter
+	BlendScreen
+)
+
+// Compositor renders into a Canvas an ordered stack of layers, every layer
+// has its own source, position, opacity and blend mode. The Canvas is only
+// rendered again when any layer changes.
+type Compositor struct {
+	// Canvas is the Canvas where the layers are rendered
+	Canvas *Canvas
+	// Background is the color below all the layers, black if nil
+	Background color.Color
+	// Clock is set to the Animations of the layers, if not nil
+	Clock Clock
+
+	m      sync.Mutex
+	layers []*Layer
+	seq    int
+	frame  *image.RGBA
+	dirty  chan struct{}
+	quit   chan struct{}
+	// playing is the chan returned by Play, nil if not playing
+	playing chan bool
+	// players are the goroutines playing the Animation layers
+	players sync.WaitGroup
+	err     error
+
+	// rm serializes the Render calls, the draw functions of the layers are
+	// called without holding m
+	rm sync.Mutex
+}
+
+// NewCompositor returns a new Compositor wrapping the given Matrix
+func NewCompositor(m Matrix) *Compositor {
+	return &Compositor{
+		Canvas: NewCanvas(m),
+		dirty:  make(chan struct{}, 1),
+	}
+}
+
+// AddImage adds a new layer on top of the stack showing the given image
+func (c *Compositor) AddImage(img image.Image) *Layer {
+	return c.add(&Layer{image: img})
+}
+
+// AddAnimation adds a new layer on top of the stack playing the given
+// Animation, the Animation is played while the Compositor is playing, and the
+// last frame remains visible when io.EOF is returned
+func (c *Compositor) AddAnimation(a Animation) *Layer {
+	return c.add(&Layer{animation: a})
+}
+
+// AddFunc adds a new layer on top of the stack of the given size, the content
+// is drawn by f, f is called again every time the layer is invalidated. f is
+// called by Render, so it can use the methods of the layers but it must not
+// call Render.
+func (c *Compositor) AddFunc(size image.Point, f func(dst *image.RGBA)) *Layer {
+	return c.add(&Layer{
+		image: image.NewRGBA(image.Rect(0, 0, size.X, size.Y)),
+		draw:  f,
+		stale: true,
+	})
+}
+
+func (c *Compositor) add(l *Layer) *Layer {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	l.c = c
+	l.opacity = 1
+	l.visible = true
+	l.seq = c.seq
+	l.stop = make(chan struct{})
+	c.seq++
+
+	c.layers = append(c.layers, l)
+	c.sortLayers()
+	if c.quit != nil && l.animation != nil {
+		c.startPlayer(l)
+	}
+
+	c.invalidate()
+	return l
+}
+
+// Remove removes the given layer from the stack
+func (c *Compositor) Remove(l *Layer) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	for i, layer := range c.layers {
+		if layer == l {
+			c.layers = append(c.layers[:i], c.layers[i+1:]...)
+			close(l.stop)
+			break
+		}
+	}
+
+	c.invalidate()
+}
+
+// sortLayers sorts the layers by z-index, keeping the order of addition for
+// the layers with the same z-index, must be called with the lock held
+func (c *Compositor) sortLayers() {
+	sort.Slice(c.layers, func(i, j int) bool {
+		if c.layers[i].z != c.layers[j].z {
+			return c.layers[i].z < c.layers[j].z
+		}
+
+		return c.layers[i].seq < c.layers[j].seq
+	})
+}
+
+func (c *Compositor) invalidate() {
+	select {
+	case c.dirty <- struct{}{}:
+	default:
+	}
+}
+
+// Render composes all the layers and renders the result into the Canvas
+func (c *Compositor) Render() error {
+	c.rm.Lock()
+	defer c.rm.Unlock()
+
+	c.drawLayers()
+
+	c.m.Lock()
+	if c.frame == nil || c.frame.Bounds() != c.Canvas.Bounds() {
+		c.frame = image.NewRGBA(c.Canvas.Bounds())
+	}
+
+	bg := c.Background
+	if bg == nil {
+		bg = color.Black
+	}
+
+	draw.Draw(c.frame, c.frame.Bounds(), &image.Uniform{bg}, image.ZP, draw.Src)
+	for _, l := range c.layers {
+		l.composeInto(c.frame)
+	}
+	c.m.Unlock()
+
+	draw.Draw(c.Canvas, c.Canvas.Bounds(), c.frame, image.ZP, draw.Src)
+	return c.Canvas.Render()
+}
+
+// Play renders the layers every time any of them changes, and plays the
+// Animation layers, until a true is sent to the returned chan. If a render
+// fails the playing is stopped, the error, or the error of any Animation, is
+// returned by Err. If the Compositor is already playing, the chan returned by
+// the previous call is returned.
+func (c *Compositor) Play() chan bool {
+	c.m.Lock()
+	if c.playing != nil {
+		defer c.m.Unlock()
+		return c.playing
+	}
+
+	// buffered, so sending to it never blocks once the playing has stopped
+	quit := make(chan bool, 1)
+	c.playing = quit
+	c.quit = make(chan struct{})
+	c.err = nil
+	for _, l := range c.layers {
+		if l.animation != nil {
+			c.startPlayer(l)
+		}
+	}
+	c.m.Unlock()
+
+	c.invalidate()
+	go func() {
+		defer c.stop()
+
+		for {
+			select {
+			case <-quit:
+				return
+			case <-c.dirty:
+				if err := c.Render(); err != nil {
+					c.m.Lock()
+					c.err = err
+					c.m.Unlock()
+					return
+				}
+			}
+		}
+	}()
+
+	return quit
+}
+
+// stop stops the Animation layers, and waits for them before allowing Play to
+// be called again, so an Animation is never played by two goroutines
+func (c *Compositor) stop() {
+	c.m.Lock()
+	close(c.quit)
+	c.quit = nil
+	c.m.Unlock()
+
+	c.players.Wait()
+
+	c.m.Lock()
+	c.playing = nil
+	c.m.Unlock()
+}
+
+// startPlayer plays the Animation of l in a new goroutine, must be called with
+// the lock held
+func (c *Compositor) startPlayer(l *Layer) {
+	c.players.Add(1)
+	go func(quit chan struct{}) {
+		defer c.players.Done()
+		l.play(quit)
+	}(c.quit)
+}
+
+// drawLayers calls the draw function of the stale layers created with AddFunc,
+// without holding the lock, so they can use the methods of the layers
+func (c *Compositor) drawLayers() {
+	type stale struct {
+		l   *Layer
+		dst *image.RGBA
+	}
+
+	var layers []stale
+	c.m.Lock()
+	for _, l := range c.layers {
+		if rgba, ok := l.image.(*image.RGBA); ok && l.draw != nil && l.stale {
+			l.stale = false
+			layers = append(layers, stale{l, rgba})
+		}
+	}
+	c.m.Unlock()
+
+	for _, s := range layers {
+		draw.Draw(s.dst, s.dst.Bounds(), image.Transparent, image.ZP, draw.Src)
+		s.l.draw(s.dst)
+	}
+}
+
+// Err returns the last error found while playing, if any
+func (c *Compositor) Err() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.err
+}
+
+// Close closes the compositor and the inner canvas
+func (c *Compositor) Close() error {
+	return c.Canvas.Close()
+}
+
+// Layer is an element of the stack of a Compositor, all the methods are safe
+// to be called concurrently and invalidate the Compositor when needed
+type Layer struct {
+	c         *Compositor
+	seq       int
+	z         int
+	position  image.Point
+	opacity   float64
+	blend     BlendMode
+	visible   bool
+	image     image.Image
+	animation Animation
+	draw      func(dst *image.RGBA)
+	stale     bool
+	stop      chan struct{}
+	// frame is a copy of the last frame of the animation, since the
+	// animations may reuse the frames while the layer is composed
+	frame *image.RGBA
+}
+
+// SetImage replaces the image shown by the layer
+func (l *Layer) SetImage(img image.Image) {
+	l.update(func() { l.image = img })
+}
+
+// SetPosition moves the top-left corner of the layer to p
+func (l *Layer) SetPosition(p image.Point) {
+	l.update(func() { l.position = p })
+}
+
+// SetOpacity sets the opacity of the layer, in the range [0, 1]
+func (l *Layer) SetOpacity(o float64) {
+	l.update(func() { l.opacity = clampf(o, 0, 1) })
+}
+
+// SetBlendMode sets how the layer is combined with the layers below
+func (l *Layer) SetBlendMode(b BlendMode) {
+	l.update(func() { l.blend = b })
+}
+
+// SetVisible shows or hides the layer
+func (l *Layer) SetVisible(v bool) {
+	l.update(func() { l.visible = v })
+}
+
+// SetZ sets the z-index of the layer, the layers with a higher z-index are
+// drawn on top, layers with the same z-index are drawn in order of addition
+func (l *Layer) SetZ(z int) {
+	l.update(func() {
+		l.z = z
+		l.c.sortLayers()
+	})
+}
+
+// Invalidate marks the layer as changed, the draw function of the layers
+// created with AddFunc is called again
+func (l *Layer) Invalidate() {
+	l.update(func() { l.stale = true })
+}
+
+func (l *Layer) update(f func()) {
+	l.c.m.Lock()
+	defer l.c.m.Unlock()
+
+	f()
+	l.c.invalidate()
+}
+
+func (l *Layer) play(quit chan struct{}) {
+	SetClock(l.animation, l.c.Clock)
+	for {
+		img, next, err := l.animation.Next()
+		if err != nil {
+			if err != io.EOF {
+				l.c.m.Lock()
+				l.c.err = err
+				l.c.m.Unlock()
+			}
+
+			return
+		}
+
+		l.setFrame(img)
+
+		select {
+		case <-next:
+		case <-quit:
+			return
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+// setFrame copies img into the frame of the layer, and shows it
+func (l *Layer) setFrame(img image.Image) {
+	l.update(func() {
+		if img == nil {
+			l.image = nil
+			return
+		}
+
+		b := img.Bounds()
+		if l.frame == nil || l.frame.Rect != b {
+			l.frame = image.NewRGBA(b)
+		}
+
+		draw.Draw(l.frame, b, img, b.Min, draw.Src)
+		l.image = l.frame
+	})
+}
+
+// composeInto blends the layer into dst, must be called with the lock held
+func (l *Layer) composeInto(dst *image.RGBA) {
+	if !l.visible || l.image == nil || l.opacity == 0 {
+		return
+	}
+
+	src := l.image
+	sb := src.Bounds()
+	r := sb.Sub(sb.Min).Add(l.position).Intersect(dst.Bounds())
+	if r.Empty() {
+		return
+	}
+
+	if l.blend == BlendNormal && l.opacity == 1 {
+		draw.Draw(dst, r, src, sb.Min.Add(r.Min.Sub(l.position)), draw.Over)
+		return
+	}
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			sp := sb.Min.Add(image.Pt(x, y).Sub(l.position))
+			i := dst.PixOffset(x, y)
+			blend(dst.Pix[i:i+4], src.At(sp.X, sp.Y), l.opacity, l.blend)
+		}
+	}
+}
+
+// blend combines the color c, with the given opacity, into the premultiplied
+// pixel px using the blend mode b
+func blend(px []uint8, c color.Color, opacity float64, b BlendMode) {
+	sr, sg, sb, sa := c.RGBA()
+	s := [4]float64{float64(sr), float64(sg), float64(sb), float64(sa)}
+	for i := range s {
+		s[i] = s[i] / 0xffff * opacity
+	}
+
+	var d [4]float64
+	for i := range d {
+		d[i] = float64(px[i]) / 0xff
+	}
+
+	for i := 0; i < 3; i++ {
+		var v float64
+		switch b {
+		case BlendAdd:
+			v = s[i] + d[i]
+		case BlendMultiply:
+			v = s[i]*d[i] + s[i]*(1-d[3]) + d[i]*(1-s[3])
+		case BlendScreen:
+			v = s[i] + d[i] - s[i]*d[i]
+		default:
+			v = s[i] + d[i]*(1-s[3])
+		}
+
+		px[i] = uint8(clampf(v, 0, 1)*0xff + .5)
+	}
+
+	px[3] = uint8(clampf(s[3]+d[3]-s[3]*d[3], 0, 1)*0xff + .5)
+}
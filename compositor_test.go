@@ -0,0 +1,148 @@
+package rgbmatrix
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type CompositorSuite struct{}
+
+var _ = Suite(&CompositorSuite{})
+
+func newTestCompositor(m Matrix) *Compositor {
+	return &Compositor{
+		Canvas: &Canvas{w: 10, h: 20, m: m},
+		dirty:  make(chan struct{}, 1),
+	}
+}
+
+func (s *CompositorSuite) TestRender(c *C) {
+	m := NewMatrixMock()
+	comp := newTestCompositor(m)
+
+	comp.AddImage(newUniformRGBA(10, 20, red))
+	l := comp.AddImage(newUniformRGBA(2, 2, blue))
+	l.SetPosition(image.Pt(1, 1))
+
+	c.Assert(comp.Render(), IsNil)
+	c.Assert(m.called["Render"], Equals, true)
+	c.Assert(m.colors[0], Equals, red)
+	c.Assert(m.colors[11], Equals, blue)
+	c.Assert(m.colors[23], Equals, red)
+}
+
+func (s *CompositorSuite) TestOrder(c *C) {
+	m := NewMatrixMock()
+	comp := newTestCompositor(m)
+
+	top := comp.AddImage(newUniformRGBA(10, 20, blue))
+	top.SetZ(1)
+	comp.AddImage(newUniformRGBA(10, 20, red))
+
+	c.Assert(comp.Render(), IsNil)
+	c.Assert(m.colors[0], Equals, blue)
+
+	top.SetVisible(false)
+	c.Assert(comp.Render(), IsNil)
+	c.Assert(m.colors[0], Equals, red)
+
+	comp.Remove(top)
+	c.Assert(comp.layers, HasLen, 1)
+}
+
+func (s *CompositorSuite) TestBlendModes(c *C) {
+	m := NewMatrixMock()
+	comp := newTestCompositor(m)
+	comp.AddImage(newUniformRGBA(10, 20, color.RGBA{200, 100, 0, 255}))
+	l := comp.AddImage(newUniformRGBA(10, 20, color.RGBA{100, 100, 100, 255}))
+
+	for mode, expected := range map[BlendMode]color.RGBA{
+		BlendNormal:   {150, 100, 50, 255},
+		BlendAdd:      {255, 200, 100, 255},
+		BlendMultiply: {78, 39, 0, 255},
+		BlendScreen:   {222, 161, 100, 255},
+	} {
+		l.SetBlendMode(mode)
+		l.SetOpacity(1)
+		if mode == BlendNormal {
+			l.SetOpacity(.5)
+		}
+
+		c.Assert(comp.Render(), IsNil)
+		c.Assert(m.colors[0], Equals, expected, Commentf("mode %d", mode))
+	}
+}
+
+func (s *CompositorSuite) TestAddFunc(c *C) {
+	m := NewMatrixMock()
+	comp := newTestCompositor(m)
+
+	calls := 0
+	l := comp.AddFunc(image.Pt(1, 1), func(dst *image.RGBA) {
+		calls++
+		dst.Set(0, 0, red)
+	})
+
+	c.Assert(comp.Render(), IsNil)
+	c.Assert(comp.Render(), IsNil)
+	c.Assert(calls, Equals, 1)
+	c.Assert(m.colors[0], Equals, red)
+
+	l.Invalidate()
+	c.Assert(comp.Render(), IsNil)
+	c.Assert(calls, Equals, 2)
+}
+
+func (s *CompositorSuite) TestAddFuncSetters(c *C) {
+	m := NewMatrixMock()
+	comp := newTestCompositor(m)
+
+	top := comp.AddImage(newUniformRGBA(10, 20, blue))
+	comp.AddFunc(image.Pt(1, 1), func(dst *image.RGBA) {
+		top.SetVisible(false)
+		dst.Set(0, 0, red)
+	})
+
+	c.Assert(comp.Render(), IsNil)
+	c.Assert(m.colors[0], Equals, red)
+	c.Assert(m.colors[1], Equals, black)
+}
+
+func (s *CompositorSuite) TestAnimationFrameCopied(c *C) {
+	m := NewMatrixMock()
+	comp := newTestCompositor(m)
+
+	// many animations draw every frame in the same image
+	frame := newUniformRGBA(10, 20, red)
+	l := comp.AddAnimation(NewImageAnimation(frame, 0))
+	l.setFrame(frame)
+	draw.Draw(frame, frame.Bounds(), &image.Uniform{blue}, image.ZP, draw.Src)
+
+	c.Assert(comp.Render(), IsNil)
+	c.Assert(m.colors[0], Equals, red)
+}
+
+func (s *CompositorSuite) TestPlayTwice(c *C) {
+	comp := newTestCompositor(NewMatrixMock())
+	comp.Clock = NewFakeClock(epoch)
+
+	quit := comp.Play()
+	c.Assert(comp.Play(), Equals, quit)
+	quit <- true
+}
+
+func (s *CompositorSuite) TestPlayRenderError(c *C) {
+	comp := newTestCompositor(failingMatrix{NewMatrixMock()})
+
+	quit := comp.Play()
+	for comp.Err() == nil {
+		time.Sleep(time.Millisecond)
+	}
+
+	c.Assert(comp.Err(), ErrorMatches, "render failed")
+	quit <- true
+}
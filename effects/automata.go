@@ -0,0 +1,203 @@
+package effects
+
+import (
+	"bytes"
+	"image"
+	"time"
+)
+
+// lifeRate is the number of generations per second of Life at speed 1
+const lifeRate = 10
+
+// lifeMaxAge is the age of a cell, in generations, at which the last color of
+// the palette is used
+const lifeMaxAge = 32
+
+// Life is Conway's Game of Life on a board wrapping around the edges, the
+// cells are colored by their age and the board is seeded again when it dies
+// or gets stuck
+type Life struct {
+	effect
+	cells, next []uint8
+	history     [][]uint8
+	acc         float64
+}
+
+// NewLife returns a new Life of the size of the given bounds, Rainbow is the
+// default palette, Density is the fraction of live cells of every seed
+func NewLife(bounds image.Rectangle, o *Options) *Life {
+	l := &Life{effect: newEffect(bounds, o, Rainbow, .3)}
+	l.cells = make([]uint8, l.size.X*l.size.Y)
+	l.next = make([]uint8, l.size.X*l.size.Y)
+	l.seed()
+
+	return l
+}
+
+func (l *Life) seed() {
+	for i := range l.cells {
+		l.cells[i] = 0
+		if l.rand.Float64() < l.density {
+			l.cells[i] = 1
+		}
+	}
+
+	l.history = nil
+}
+
+// Next returns the next frame of the effect
+func (l *Life) Next() (image.Image, <-chan time.Time, error) {
+	for i := l.steps(lifeRate, &l.acc); i > 0; i-- {
+		l.step()
+	}
+
+	l.clear()
+	for i, age := range l.cells {
+		if age == 0 {
+			continue
+		}
+
+		l.frame.SetRGBA(i%l.size.X, i/l.size.X, l.palette.At(float64(age-1)/lifeMaxAge))
+	}
+
+	return l.frame, l.tick(), nil
+}
+
+func (l *Life) step() {
+	w, h := l.size.X, l.size.Y
+	alive := false
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			n := 0
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					if (dx != 0 || dy != 0) && l.cells[(y+dy+h)%h*w+(x+dx+w)%w] > 0 {
+						n++
+					}
+				}
+			}
+
+			i := y*w + x
+			age := l.cells[i]
+			switch {
+			case age > 0 && (n == 2 || n == 3):
+				if age < lifeMaxAge+1 {
+					age++
+				}
+			case age == 0 && n == 3:
+				age = 1
+			default:
+				age = 0
+			}
+
+			l.next[i] = age
+			alive = alive || age > 0
+		}
+	}
+
+	l.cells, l.next = l.next, l.cells
+	if !alive || l.stuck() {
+		l.seed()
+	}
+}
+
+// lifeHistory is the number of generations compared to detect a stuck board,
+// it catches still lifes and oscillators with a period up to it
+const lifeHistory = 16
+
+// stuck returns true if the board repeats one of the last generations
+func (l *Life) stuck() bool {
+	state := make([]uint8, len(l.cells))
+	for i, age := range l.cells {
+		if age > 0 {
+			state[i] = 1
+		}
+	}
+
+	for _, prev := range l.history {
+		if bytes.Equal(prev, state) {
+			return true
+		}
+	}
+
+	l.history = append(l.history, state)
+	if len(l.history) > lifeHistory {
+		l.history = l.history[1:]
+	}
+
+	return false
+}
+
+// antRate is the number of moves per second of every ant at speed 1
+const antRate = 60
+
+// LangtonsAnt is Langton's ant on a board wrapping around the edges, every
+// ant turns right on an empty cell, left on a painted one, flips the cell and
+// moves forward. Every ant paints with its own color of the palette.
+type LangtonsAnt struct {
+	effect
+	ants  []ant
+	cells []int
+	acc   float64
+}
+
+type ant struct {
+	x, y, dir int
+}
+
+var antMoves = [4]image.Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+
+// NewLangtonsAnt returns a new LangtonsAnt of the size of the given bounds,
+// Rainbow is the default palette, Density is the number of ants
+func NewLangtonsAnt(bounds image.Rectangle, o *Options) *LangtonsAnt {
+	a := &LangtonsAnt{effect: newEffect(bounds, o, Rainbow, .1)}
+	a.cells = make([]int, a.size.X*a.size.Y)
+
+	n := 1 + int(a.density*10)
+	for i := 0; i < n && a.size.X > 0 && a.size.Y > 0; i++ {
+		a.ants = append(a.ants, ant{
+			x:   a.rand.Intn(a.size.X),
+			y:   a.rand.Intn(a.size.Y),
+			dir: a.rand.Intn(4),
+		})
+	}
+
+	return a
+}
+
+// Next returns the next frame of the effect
+func (a *LangtonsAnt) Next() (image.Image, <-chan time.Time, error) {
+	for i := a.steps(antRate, &a.acc); i > 0; i-- {
+		a.step()
+	}
+
+	a.clear()
+	for i, c := range a.cells {
+		if c == 0 {
+			continue
+		}
+
+		v := float64(c-1) / float64(len(a.ants))
+		a.frame.SetRGBA(i%a.size.X, i/a.size.X, a.palette.At(v))
+	}
+
+	return a.frame, a.tick(), nil
+}
+
+func (a *LangtonsAnt) step() {
+	w, h := a.size.X, a.size.Y
+	for i := range a.ants {
+		ant := &a.ants[i]
+		c := &a.cells[ant.y*w+ant.x]
+		if *c == 0 {
+			ant.dir = (ant.dir + 1) % 4
+			*c = i + 1
+		} else {
+			ant.dir = (ant.dir + 3) % 4
+			*c = 0
+		}
+
+		m := antMoves[ant.dir]
+		ant.x, ant.y = (ant.x+m.X+w)%w, (ant.y+m.Y+h)%h
+	}
+}
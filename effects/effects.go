@@ -0,0 +1,195 @@
+// Package effects contains classic LED matrix effects, like plasma, fire or
+// the Game of Life, implementing the Animation interface. Every effect is
+// sized from the bounds given, usually the Canvas bounds, and never ends.
+package effects
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+)
+
+// DefaultFrameRate is the frames per second rendered by the effects when no
+// FrameRate is given
+const DefaultFrameRate = 30
+
+// Options are the parameters shared by all the effects
+type Options struct {
+	// Speed multiplies the speed of the effect, 1 if zero
+	Speed float64
+	// Palette of the effect, every effect has its own default palette
+	Palette Palette
+	// Density, in the range [0, 1], of the elements of the effect, like the
+	// stars, the drops or the live cells, every effect has its own default
+	Density float64
+	// FrameRate is the frames per second rendered, DefaultFrameRate if zero
+	FrameRate int
+	// Seed of the random generator, a seed based on the time is used if zero
+	Seed int64
+}
+
+// effect contains the common state of all the effects
+type effect struct {
+	rgbmatrix.Clocked
+
+	size    image.Point
+	speed   float64
+	palette Palette
+	density float64
+	delay   time.Duration
+	rand    *rand.Rand
+	frame   *image.RGBA
+	// t is the time elapsed in the effect, in seconds, scaled by the speed
+	t float64
+}
+
+func newEffect(bounds image.Rectangle, o *Options, palette Palette, density float64) effect {
+	if o == nil {
+		o = &Options{}
+	}
+
+	e := effect{
+		size:    bounds.Size(),
+		speed:   o.Speed,
+		palette: o.Palette,
+		density: o.Density,
+		delay:   time.Second / DefaultFrameRate,
+		frame:   image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy())),
+	}
+
+	if e.speed == 0 {
+		e.speed = 1
+	}
+
+	if e.palette == nil {
+		e.palette = palette
+	}
+
+	if e.density == 0 {
+		e.density = density
+	}
+
+	if o.FrameRate > 0 {
+		e.delay = time.Second / time.Duration(o.FrameRate)
+	}
+
+	seed := o.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	e.rand = rand.New(rand.NewSource(seed))
+	return e
+}
+
+// tick advances the time of the effect by a frame, and returns the chan
+// to wait for the next frame
+func (e *effect) tick() <-chan time.Time {
+	e.t += e.delay.Seconds() * e.speed
+	return e.Clock().After(e.delay)
+}
+
+// steps returns how many steps of a discrete simulation, running at the given
+// steps per second, have to be run in the next frame, the fraction of step
+// left is accumulated in acc
+func (e *effect) steps(perSecond float64, acc *float64) int {
+	*acc += perSecond * e.speed * e.delay.Seconds()
+	n := math.Floor(*acc)
+	*acc -= n
+	return int(n)
+}
+
+// fade multiplies all the pixels of the frame by f
+func (e *effect) fade(f float64) {
+	for i := range e.frame.Pix {
+		if i%4 != 3 {
+			e.frame.Pix[i] = uint8(float64(e.frame.Pix[i]) * f)
+		}
+	}
+}
+
+// clear fills the frame with black
+func (e *effect) clear() {
+	for i := range e.frame.Pix {
+		e.frame.Pix[i] = 0
+		if i%4 == 3 {
+			e.frame.Pix[i] = 0xff
+		}
+	}
+}
+
+// Palette is a gradient between a list of colors
+type Palette []color.Color
+
+// At returns the color of the gradient at v, in the range [0, 1]
+func (p Palette) At(v float64) color.RGBA {
+	if len(p) == 0 {
+		return color.RGBA{}
+	}
+
+	v = math.Max(0, math.Min(1, v))
+	f := v * float64(len(p)-1)
+	i := int(f)
+	if i >= len(p)-1 {
+		return color.RGBAModel.Convert(p[len(p)-1]).(color.RGBA)
+	}
+
+	a := color.RGBAModel.Convert(p[i]).(color.RGBA)
+	b := color.RGBAModel.Convert(p[i+1]).(color.RGBA)
+	f -= float64(i)
+
+	return color.RGBA{
+		R: lerp(a.R, b.R, f),
+		G: lerp(a.G, b.G, f),
+		B: lerp(a.B, b.B, f),
+		A: lerp(a.A, b.A, f),
+	}
+}
+
+func lerp(a, b uint8, f float64) uint8 {
+	return uint8(float64(a) + (float64(b)-float64(a))*f + .5)
+}
+
+var (
+	// Rainbow goes through all the hues
+	Rainbow = Palette{
+		color.RGBA{255, 0, 0, 255},
+		color.RGBA{255, 255, 0, 255},
+		color.RGBA{0, 255, 0, 255},
+		color.RGBA{0, 255, 255, 255},
+		color.RGBA{0, 0, 255, 255},
+		color.RGBA{255, 0, 255, 255},
+		color.RGBA{255, 0, 0, 255},
+	}
+	// Flames goes from black to white through red and yellow
+	Flames = Palette{
+		color.RGBA{0, 0, 0, 255},
+		color.RGBA{128, 0, 0, 255},
+		color.RGBA{255, 64, 0, 255},
+		color.RGBA{255, 192, 0, 255},
+		color.RGBA{255, 255, 224, 255},
+	}
+	// Ocean goes from black to white through blue and cyan
+	Ocean = Palette{
+		color.RGBA{0, 0, 32, 255},
+		color.RGBA{0, 32, 128, 255},
+		color.RGBA{0, 160, 200, 255},
+		color.RGBA{224, 255, 255, 255},
+	}
+	// Phosphor goes from black to white through green
+	Phosphor = Palette{
+		color.RGBA{0, 0, 0, 255},
+		color.RGBA{0, 96, 0, 255},
+		color.RGBA{0, 255, 64, 255},
+		color.RGBA{200, 255, 200, 255},
+	}
+	// Grayscale goes from black to white
+	Grayscale = Palette{
+		color.RGBA{0, 0, 0, 255},
+		color.RGBA{255, 255, 255, 255},
+	}
+)
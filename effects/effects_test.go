@@ -0,0 +1,72 @@
+package effects
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type EffectsSuite struct{}
+
+var _ = Suite(&EffectsSuite{})
+
+func (s *EffectsSuite) TestPaletteAt(c *C) {
+	p := Palette{color.Black, color.White}
+	c.Assert(p.At(0), Equals, color.RGBA{0, 0, 0, 255})
+	c.Assert(p.At(.5), Equals, color.RGBA{128, 128, 128, 255})
+	c.Assert(p.At(1), Equals, color.RGBA{255, 255, 255, 255})
+	c.Assert(p.At(2), Equals, color.RGBA{255, 255, 255, 255})
+	c.Assert(Palette{}.At(.5), Equals, color.RGBA{})
+}
+
+func (s *EffectsSuite) TestSizedFromBounds(c *C) {
+	bounds := image.Rect(0, 0, 16, 8)
+	o := &Options{Seed: 42, FrameRate: 1000}
+
+	for _, a := range []rgbmatrix.Animation{
+		NewPlasma(bounds, o),
+		NewRainbowWheel(bounds, o),
+		NewMetaballs(bounds, o),
+		NewFire(bounds, o),
+		NewStarfield(bounds, o),
+		NewMatrixRain(bounds, o),
+		NewLife(bounds, o),
+		NewLangtonsAnt(bounds, o),
+	} {
+		for i := 0; i < 3; i++ {
+			img, _, err := a.Next()
+			c.Assert(err, IsNil)
+			c.Assert(img.Bounds(), Equals, image.Rect(0, 0, 16, 8))
+		}
+	}
+}
+
+func (s *EffectsSuite) TestLifeBlinker(c *C) {
+	l := NewLife(image.Rect(0, 0, 5, 5), &Options{Seed: 1})
+	for i := range l.cells {
+		l.cells[i] = 0
+	}
+
+	l.cells[2*5+1], l.cells[2*5+2], l.cells[2*5+3] = 1, 1, 1
+	l.history = nil
+	l.step()
+
+	c.Assert(l.cells[1*5+2] > 0, Equals, true)
+	c.Assert(l.cells[2*5+2], Equals, uint8(2))
+	c.Assert(l.cells[3*5+2] > 0, Equals, true)
+	c.Assert(l.cells[2*5+1], Equals, uint8(0))
+}
+
+func (s *EffectsSuite) TestLangtonsAntStep(c *C) {
+	a := NewLangtonsAnt(image.Rect(0, 0, 5, 5), &Options{Seed: 1, Density: .01})
+	a.ants = []ant{{x: 2, y: 2, dir: 0}}
+	a.step()
+
+	c.Assert(a.cells[2*5+2], Equals, 1)
+	c.Assert(a.ants[0], Equals, ant{x: 3, y: 2, dir: 1})
+}
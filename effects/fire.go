@@ -0,0 +1,72 @@
+package effects
+
+import (
+	"image"
+	"time"
+)
+
+// fireRate is the number of steps per second of the fire simulation at speed 1
+const fireRate = 30
+
+// Fire is the classic fire effect, heat rises from the bottom row cooling down
+// randomly on the way up
+type Fire struct {
+	effect
+	heat []float64
+	acc  float64
+}
+
+// NewFire returns a new Fire of the size of the given bounds, Flames is the
+// default palette, Density is the height of the flames
+func NewFire(bounds image.Rectangle, o *Options) *Fire {
+	f := &Fire{effect: newEffect(bounds, o, Flames, .5)}
+	f.heat = make([]float64, f.size.X*f.size.Y)
+
+	return f
+}
+
+// Next returns the next frame of the effect
+func (f *Fire) Next() (image.Image, <-chan time.Time, error) {
+	for i := f.steps(fireRate, &f.acc); i > 0; i-- {
+		f.step()
+	}
+
+	for y := 0; y < f.size.Y; y++ {
+		for x := 0; x < f.size.X; x++ {
+			f.frame.SetRGBA(x, y, f.palette.At(f.heat[y*f.size.X+x]))
+		}
+	}
+
+	return f.frame, f.tick(), nil
+}
+
+func (f *Fire) step() {
+	w, h := f.size.X, f.size.Y
+	if w == 0 || h == 0 {
+		return
+	}
+
+	for x := 0; x < w; x++ {
+		f.heat[(h-1)*w+x] = .7 + f.rand.Float64()*.3
+	}
+
+	// the higher the density the less the heat is cooled down every row
+	cooling := (1.2 - f.density) * 3 / float64(h)
+	for y := 0; y < h-1; y++ {
+		for x := 0; x < w; x++ {
+			src := x + f.rand.Intn(3) - 1
+			if src < 0 {
+				src = 0
+			} else if src >= w {
+				src = w - 1
+			}
+
+			v := f.heat[(y+1)*w+src] - f.rand.Float64()*cooling
+			if v < 0 {
+				v = 0
+			}
+
+			f.heat[y*w+x] = v
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package effects
+
+import (
+	"image"
+	"time"
+)
+
+// Starfield is a flight through a field of stars
+type Starfield struct {
+	effect
+	stars []star
+}
+
+type star struct {
+	x, y, z float64
+}
+
+// NewStarfield returns a new Starfield of the size of the given bounds,
+// Grayscale is the default palette, Density is the number of stars
+func NewStarfield(bounds image.Rectangle, o *Options) *Starfield {
+	s := &Starfield{effect: newEffect(bounds, o, Grayscale, .2)}
+
+	n := 1 + int(s.density*float64(s.size.X*s.size.Y)/4)
+	s.stars = make([]star, n)
+	for i := range s.stars {
+		s.stars[i] = s.newStar(s.rand.Float64())
+	}
+
+	return s
+}
+
+// newStar returns a star at a random position at the given depth, in the
+// range (0, 1]
+func (s *Starfield) newStar(z float64) star {
+	return star{x: s.rand.Float64()*2 - 1, y: s.rand.Float64()*2 - 1, z: z + .001}
+}
+
+// Next returns the next frame of the effect
+func (s *Starfield) Next() (image.Image, <-chan time.Time, error) {
+	s.clear()
+
+	dz := s.delay.Seconds() * s.speed / 2
+	cx, cy := float64(s.size.X)/2, float64(s.size.Y)/2
+	for i := range s.stars {
+		st := &s.stars[i]
+		st.z -= dz
+
+		x := int(cx + st.x/st.z*cx)
+		y := int(cy + st.y/st.z*cy)
+		if st.z <= 0 || !(image.Point{x, y}).In(s.frame.Rect) {
+			*st = s.newStar(1)
+			continue
+		}
+
+		s.frame.SetRGBA(x, y, s.palette.At(1-st.z))
+	}
+
+	return s.frame, s.tick(), nil
+}
+
+// matrixRainRate is the number of rows per second fallen by the drops of the
+// MatrixRain at speed 1
+const matrixRainRate = 15
+
+// MatrixRain are green drops falling leaving a fading trail
+type MatrixRain struct {
+	effect
+	drops []float64
+	acc   float64
+}
+
+// NewMatrixRain returns a new MatrixRain of the size of the given bounds,
+// Phosphor is the default palette, Density is the probability of a new drop
+// in every column
+func NewMatrixRain(bounds image.Rectangle, o *Options) *MatrixRain {
+	r := &MatrixRain{effect: newEffect(bounds, o, Phosphor, .3)}
+	r.clear()
+
+	r.drops = make([]float64, r.size.X)
+	for i := range r.drops {
+		r.drops[i] = -1
+	}
+
+	return r
+}
+
+// Next returns the next frame of the effect
+func (r *MatrixRain) Next() (image.Image, <-chan time.Time, error) {
+	for i := r.steps(matrixRainRate, &r.acc); i > 0; i-- {
+		r.step()
+	}
+
+	return r.frame, r.tick(), nil
+}
+
+func (r *MatrixRain) step() {
+	r.fade(.85)
+
+	for x, y := range r.drops {
+		if y < 0 {
+			if r.rand.Float64() < r.density/10 {
+				r.drops[x] = 0
+			}
+
+			continue
+		}
+
+		r.frame.SetRGBA(x, int(y), r.palette.At(.7+r.rand.Float64()*.3))
+		if y++; int(y) >= r.size.Y {
+			y = -1
+		}
+
+		r.drops[x] = y
+	}
+}
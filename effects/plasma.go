@@ -0,0 +1,125 @@
+package effects
+
+import (
+	"image"
+	"math"
+	"time"
+)
+
+// Plasma is the classic demoscene plasma, a sum of sine waves
+type Plasma struct {
+	effect
+}
+
+// NewPlasma returns a new Plasma of the size of the given bounds, Rainbow is
+// the default palette, Density changes the scale of the waves
+func NewPlasma(bounds image.Rectangle, o *Options) *Plasma {
+	return &Plasma{effect: newEffect(bounds, o, Rainbow, .5)}
+}
+
+// Next returns the next frame of the effect
+func (p *Plasma) Next() (image.Image, <-chan time.Time, error) {
+	scale := 2 + 14*(1-p.density)
+	for y := 0; y < p.size.Y; y++ {
+		for x := 0; x < p.size.X; x++ {
+			fx, fy := float64(x)/scale, float64(y)/scale
+			v := math.Sin(fx+p.t) +
+				math.Sin(fy+p.t*1.3) +
+				math.Sin((fx+fy+p.t*.7)/2) +
+				math.Sin(math.Hypot(fx-float64(p.size.X)/scale/2, fy-float64(p.size.Y)/scale/2)-p.t)
+
+			p.frame.SetRGBA(x, y, p.palette.At((v+4)/8))
+		}
+	}
+
+	return p.frame, p.tick(), nil
+}
+
+// RainbowWheel is a color wheel rotating around the center
+type RainbowWheel struct {
+	effect
+}
+
+// NewRainbowWheel returns a new RainbowWheel of the size of the given bounds,
+// Rainbow is the default palette, Density is the number of turns of the
+// palette in the wheel
+func NewRainbowWheel(bounds image.Rectangle, o *Options) *RainbowWheel {
+	return &RainbowWheel{effect: newEffect(bounds, o, Rainbow, .25)}
+}
+
+// Next returns the next frame of the effect
+func (w *RainbowWheel) Next() (image.Image, <-chan time.Time, error) {
+	turns := math.Max(1, math.Floor(w.density*4+.5))
+	cx, cy := float64(w.size.X)/2, float64(w.size.Y)/2
+	for y := 0; y < w.size.Y; y++ {
+		for x := 0; x < w.size.X; x++ {
+			a := math.Atan2(float64(y)+.5-cy, float64(x)+.5-cx)/(2*math.Pi) + .5
+			v := a*turns + w.t/4
+			w.frame.SetRGBA(x, y, w.palette.At(v-math.Floor(v)))
+		}
+	}
+
+	return w.frame, w.tick(), nil
+}
+
+// Metaballs are blobs bouncing around and merging when they are close
+type Metaballs struct {
+	effect
+	balls []ball
+}
+
+type ball struct {
+	x, y, vx, vy, r float64
+}
+
+// NewMetaballs returns new Metaballs of the size of the given bounds, Ocean is
+// the default palette, Density is the number of balls
+func NewMetaballs(bounds image.Rectangle, o *Options) *Metaballs {
+	m := &Metaballs{effect: newEffect(bounds, o, Ocean, .3)}
+
+	n := 2 + int(m.density*10)
+	min := math.Min(float64(m.size.X), float64(m.size.Y))
+	for i := 0; i < n; i++ {
+		a := m.rand.Float64() * 2 * math.Pi
+		v := min * (.2 + m.rand.Float64()*.3)
+		m.balls = append(m.balls, ball{
+			x:  m.rand.Float64() * float64(m.size.X),
+			y:  m.rand.Float64() * float64(m.size.Y),
+			vx: math.Cos(a) * v,
+			vy: math.Sin(a) * v,
+			r:  min * (.12 + m.rand.Float64()*.12),
+		})
+	}
+
+	return m
+}
+
+// Next returns the next frame of the effect
+func (m *Metaballs) Next() (image.Image, <-chan time.Time, error) {
+	for y := 0; y < m.size.Y; y++ {
+		for x := 0; x < m.size.X; x++ {
+			var sum float64
+			for _, b := range m.balls {
+				dx, dy := float64(x)+.5-b.x, float64(y)+.5-b.y
+				sum += b.r * b.r / (dx*dx + dy*dy + 1)
+			}
+
+			m.frame.SetRGBA(x, y, m.palette.At(sum/2))
+		}
+	}
+
+	dt := m.delay.Seconds() * m.speed
+	for i := range m.balls {
+		b := &m.balls[i]
+		b.x, b.y = b.x+b.vx*dt, b.y+b.vy*dt
+		if b.x < 0 || b.x > float64(m.size.X) {
+			b.vx = -b.vx
+		}
+
+		if b.y < 0 || b.y > float64(m.size.Y) {
+			b.vy = -b.vy
+		}
+	}
+
+	return m.frame, m.tick(), nil
+}
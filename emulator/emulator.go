@@ -1,14 +1,20 @@
 package emulator
 
 import (
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
 	"os"
 	"sync"
+	"time"
 
+	"github.com/mcuadros/go-rpi-rgb-led-matrix/input"
 	"golang.org/x/exp/shiny/driver"
 	"golang.org/x/exp/shiny/screen"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/lifecycle"
+	"golang.org/x/mobile/event/mouse"
 	"golang.org/x/mobile/event/paint"
 	"golang.org/x/mobile/event/size"
 )
@@ -16,6 +22,9 @@ import (
 const DefaultPixelPitch = 12
 const windowTitle = "RGB led matrix emulator"
 
+// ErrClosed is returned when drawing in an emulator closed, or not initialized
+var ErrClosed = errors.New("emulator: closed")
+
 type Emulator struct {
 	PixelPitch              int
 	Gutter                  int
@@ -24,15 +33,54 @@ type Emulator struct {
 	GutterColor             color.Color
 	PixelPitchToGutterRatio int
 	Margin                  int
-
-	leds []color.Color
-	w    screen.Window
-	s    screen.Screen
-	wg   sync.WaitGroup
+	// Panel simulates the colors shown by a real panel, exact colors if nil
+	Panel *Panel
+	// RoundLEDs draws round LEDs instead of squares
+	RoundLEDs bool
+	// Glow is the radius in pixels of the light diffused around the LEDs
+	Glow int
+	// ScreenshotKey is the key saving a screenshot of the window as a PNG
+	// file, in ScreenshotDir, the key is not sent as an input event
+	ScreenshotKey input.Key
+	// ScreenshotDir is the directory of the screenshots, the current
+	// directory if empty
+	ScreenshotDir string
+	// Overlay shows the frames per second, the time drawing every frame and
+	// the coordinates and color of the LED under the mouse
+	Overlay bool
+	// OverlayKey is the key toggling the Overlay, the key is not sent as an
+	// input event
+	OverlayKey input.Key
+	// Topology is how the LEDs are split in panels, if any
+	Topology *Topology
+	// ShowTopology outlines every panel of the Topology with its position in
+	// the chain and its parallel output, and marks the scan order of the rows
+	ShowTopology bool
+	// TopologyKey is the key toggling ShowTopology, the key is not sent as an
+	// input event
+	TopologyKey input.Key
+
+	leds    []color.Color
+	w       screen.Window
+	s       screen.Screen
+	wg      sync.WaitGroup
+	input   *input.Queue
+	mouse   image.Point
+	done    chan struct{}
+	m       sync.Mutex
+	last    []color.Color
+	buffer  screen.Buffer
+	closed  bool
+	stats   stats
+	hover   image.Point
+	hovered bool
 
 	isReady bool
 }
 
+// closeEvent is sent to the window to end its event loop
+type closeEvent struct{}
+
 func NewEmulator(w, h, pixelPitch int, autoInit bool) *Emulator {
 	e := &Emulator{
 		Width:                   w,
@@ -40,6 +88,11 @@ func NewEmulator(w, h, pixelPitch int, autoInit bool) *Emulator {
 		GutterColor:             color.Gray{Y: 20},
 		PixelPitchToGutterRatio: 2,
 		Margin:                  10,
+		ScreenshotKey:           input.KeyF12,
+		OverlayKey:              input.KeyF10,
+		TopologyKey:             input.KeyF9,
+		input:                   input.NewQueue(0),
+		last:                    make([]color.Color, w*h),
 	}
 	e.updatePixelPitchForGutter(pixelPitch / e.PixelPitchToGutterRatio)
 
@@ -54,9 +107,14 @@ func NewEmulator(w, h, pixelPitch int, autoInit bool) *Emulator {
 // painted. If something goes wrong the function panics
 func (e *Emulator) Init() {
 	e.leds = make([]color.Color, e.Width*e.Height)
+	e.done = make(chan struct{})
 
 	e.wg.Add(1)
-	go driver.Main(e.mainWindowLoop)
+	go func() {
+		defer close(e.done)
+		driver.Main(e.mainWindowLoop)
+	}()
+
 	e.wg.Wait()
 }
 
@@ -75,7 +133,7 @@ func (e *Emulator) mainWindowLoop(s screen.Screen) {
 		panic(err)
 	}
 
-	defer e.w.Release()
+	defer e.release()
 
 	var sz size.Event
 	for {
@@ -87,26 +145,82 @@ func (e *Emulator) mainWindowLoop(s screen.Screen) {
 				continue
 			}
 
-			e.Apply(make([]color.Color, e.Width*e.Height))
 			e.wg.Done()
 			e.isReady = true
 		case size.Event:
 			sz = evn
 
+		case key.Event:
+			ev := keyEvent(evn)
+			if ev.Key == e.ScreenshotKey && ev.Type == input.KeyPress {
+				e.saveScreenshot()
+				continue
+			}
+
+			if ev.Key == e.OverlayKey && ev.Type == input.KeyPress {
+				e.toggle(&e.Overlay)
+				continue
+			}
+
+			if ev.Key == e.TopologyKey && ev.Type == input.KeyPress {
+				e.toggle(&e.ShowTopology)
+				continue
+			}
+
+			e.input.Push(ev)
+
+		case mouse.Event:
+			e.updateHover(evn)
+			if ev, ok := mouseEvent(evn, e.layout(), e.mouse); ok {
+				e.mouse = image.Pt(ev.X, ev.Y)
+				e.input.Push(ev)
+			}
+
+		case lifecycle.Event:
+			if evn.To == lifecycle.StageDead {
+				return
+			}
+
+		case closeEvent:
+			return
+
 		case error:
-			fmt.Fprintln(os.Stderr, e)
+			fmt.Fprintln(os.Stderr, evn)
 		}
 	}
 }
 
+// release releases the window when the event loop ends, the emulator can't
+// be used after
+func (e *Emulator) release() {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.closed = true
+	if e.buffer != nil {
+		e.buffer.Release()
+		e.buffer = nil
+	}
+
+	e.w.Release()
+	e.input.Close()
+
+	// unblocks Init if the window was closed before being painted
+	if !e.isReady {
+		e.wg.Done()
+		e.isReady = true
+	}
+}
+
 func (e *Emulator) drawContext(sz size.Event) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
 	e.updatePixelPitchForGutter(e.calculateGutterForViewableArea(sz.Size()))
 	// Fill entire background with white.
 	e.w.Fill(sz.Bounds(), color.White, screen.Src)
-	// Fill matrix display rectangle with the gutter color.
-	e.w.Fill(e.matrixWithMarginsRect(), e.GutterColor, screen.Src)
-	// Set all LEDs to black.
-	e.Apply(make([]color.Color, e.Width*e.Height))
+	// Draw again the last frame.
+	e.draw()
 }
 
 // Some formulas that allowed me to better understand the drawable area. I found that the math was
@@ -135,16 +249,20 @@ func (e *Emulator) drawContext(sz size.Event) {
 
 // matrixWithMarginsRect Returns a Rectangle that describes entire emulated RGB Matrix, including margins.
 func (e *Emulator) matrixWithMarginsRect() image.Rectangle {
-	upperLeftLED := e.ledRect(0, 0)
-	lowerRightLED := e.ledRect(e.Width-1, e.Height-1)
-	return image.Rect(upperLeftLED.Min.X-e.Margin, upperLeftLED.Min.Y-e.Margin, lowerRightLED.Max.X+e.Margin, lowerRightLED.Max.Y+e.Margin)
+	return e.layout().matrixWithMarginsRect()
 }
 
 // ledRect Returns a Rectangle for the LED at col and row.
 func (e *Emulator) ledRect(col int, row int) image.Rectangle {
-	x := (col * (e.PixelPitch + e.Gutter)) + e.Margin
-	y := (row * (e.PixelPitch + e.Gutter)) + e.Margin
-	return image.Rect(x, y, x+e.PixelPitch, y+e.PixelPitch)
+	return e.layout().ledRect(col, row)
+}
+
+func (e *Emulator) layout() layout {
+	return layout{
+		width: e.Width, height: e.Height,
+		pitch: e.PixelPitch, gutter: e.Gutter, margin: e.Margin,
+		panel: e.Panel, round: e.RoundLEDs, glow: e.Glow,
+	}
 }
 
 // calculateGutterForViewableArea As the name states, calculates the size of the gutter for a given viewable area.
@@ -168,21 +286,90 @@ func (e *Emulator) Geometry() (width, height int) {
 	return e.Width, e.Height
 }
 
+// Apply draws the given leds in the window
 func (e *Emulator) Apply(leds []color.Color) error {
 	defer func() { e.leds = make([]color.Color, e.Height*e.Width) }()
 
-	var c color.Color
-	for col := 0; col < e.Width; col++ {
-		for row := 0; row < e.Height; row++ {
-			c = e.At(col + (row * e.Width))
-			e.w.Fill(e.ledRect(col, row), c, screen.Over)
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	if e.closed || e.w == nil {
+		return ErrClosed
+	}
+
+	copy(e.last, leds)
+	return e.draw()
+}
+
+// draw draws the last frame in a buffer uploaded at once to the window, the
+// lock must be held
+func (e *Emulator) draw() error {
+	start := time.Now()
+	l := e.layout()
+	r := l.matrixWithMarginsRect()
+	if e.buffer == nil || e.buffer.Size() != r.Max {
+		if e.buffer != nil {
+			e.buffer.Release()
 		}
+
+		var err error
+		e.buffer, err = e.s.NewBuffer(r.Max)
+		if err != nil {
+			e.buffer = nil
+			return err
+		}
+	}
+
+	img := e.buffer.RGBA()
+	l.draw(img, e.last, e.GutterColor)
+	if e.ShowTopology && e.Topology != nil {
+		l.drawTopology(img, *e.Topology)
 	}
 
+	if e.Overlay {
+		drawOverlay(img, e.overlay())
+	}
+
+	e.w.Upload(r.Min, e.buffer, r)
 	e.w.Publish()
+	e.stats.frame(time.Now(), time.Since(start))
 	return nil
 }
 
+// redraw draws again the last frame, used to update the overlay
+func (e *Emulator) redraw() {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	if !e.closed {
+		e.draw()
+	}
+}
+
+// toggle toggles the option v, redrawing the last frame
+func (e *Emulator) toggle(v *bool) {
+	e.m.Lock()
+	*v = !*v
+	e.m.Unlock()
+
+	e.redraw()
+}
+
+// updateHover updates the LED under the mouse, redrawing the overlay if shown
+func (e *Emulator) updateHover(m mouse.Event) {
+	p, ok := e.layout().ledAt(int(m.X), int(m.Y))
+
+	e.m.Lock()
+	changed := p != e.hover || ok != e.hovered
+	e.hover, e.hovered = p, ok
+	overlay := e.Overlay
+	e.m.Unlock()
+
+	if changed && overlay {
+		e.redraw()
+	}
+}
+
 func (e *Emulator) Render() error {
 	return e.Apply(e.leds)
 }
@@ -199,6 +386,20 @@ func (e *Emulator) Set(position int, c color.Color) {
 	e.leds[position] = color.RGBAModel.Convert(c)
 }
 
+// Close closes the window, waiting until its event loop ends
 func (e *Emulator) Close() error {
+	e.m.Lock()
+	if e.closed || e.w == nil {
+		e.closed = true
+		e.m.Unlock()
+		e.input.Close()
+		return nil
+	}
+
+	e.closed = true
+	e.m.Unlock()
+
+	e.w.Send(closeEvent{})
+	<-e.done
 	return nil
 }
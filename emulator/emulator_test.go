@@ -0,0 +1,84 @@
+package emulator
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type EmulatorSuite struct{}
+
+var _ = Suite(&EmulatorSuite{})
+
+func (s *EmulatorSuite) TestApplyNotInitialized(c *C) {
+	e := NewEmulator(2, 2, 12, false)
+	c.Assert(e.Apply(make([]color.Color, 4)), Equals, ErrClosed)
+	c.Assert(e.Close(), IsNil)
+
+	_, ok := <-e.Events()
+	c.Assert(ok, Equals, false)
+}
+
+func (s *EmulatorSuite) TestScreenshot(c *C) {
+	e := NewEmulator(2, 2, 12, false)
+	e.last[3] = red
+
+	img := e.Screenshot()
+	c.Assert(img.Bounds(), Equals, image.Rect(0, 0, 50, 50))
+	c.Assert(img.At(39, 39), Equals, color.Color(red))
+
+	// the emulator and the headless emulator look the same
+	h := NewHeadless(2, 2, 12)
+	c.Assert(h.Apply(e.last), IsNil)
+	c.Assert(img, DeepEquals, h.Image(h.Frames()[0]))
+}
+
+func (s *EmulatorSuite) TestSaveScreenshot(c *C) {
+	e := NewEmulator(2, 2, 12, false)
+	e.ScreenshotDir = c.MkDir()
+	e.saveScreenshot()
+
+	files, err := filepath.Glob(filepath.Join(e.ScreenshotDir, "emulator-*.png"))
+	c.Assert(err, IsNil)
+	c.Assert(files, HasLen, 1)
+
+	f, err := os.Open(files[0])
+	c.Assert(err, IsNil)
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	c.Assert(err, IsNil)
+	c.Assert(img.Bounds(), Equals, image.Rect(0, 0, 50, 50))
+}
+
+func (s *EmulatorSuite) TestStats(c *C) {
+	var st stats
+	now := time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i <= 30; i++ {
+		st.frame(now.Add(time.Duration(i)*time.Second/30), time.Millisecond)
+	}
+
+	c.Assert(st.fps, Equals, 30.0)
+	c.Assert(st.draw, Equals, time.Millisecond)
+}
+
+func (s *EmulatorSuite) TestOverlay(c *C) {
+	e := NewEmulator(2, 2, 12, false)
+	e.last[3] = red
+	e.stats.fps = 59.94
+	e.stats.draw = 1234 * time.Microsecond
+	c.Assert(e.overlay(), DeepEquals, []string{"59.9 fps 1.23ms"})
+
+	e.hover, e.hovered = image.Pt(1, 1), true
+	c.Assert(e.overlay(), DeepEquals, []string{"59.9 fps 1.23ms", "1,1 #ff0000"})
+
+	img := image.NewRGBA(image.Rect(0, 0, 100, 50))
+	drawOverlay(img, e.overlay())
+	c.Assert(img.RGBAAt(0, 0), Equals, color.RGBA{0, 0, 0, 192})
+	c.Assert(img.RGBAAt(99, 49), Equals, color.RGBA{})
+}
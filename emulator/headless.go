@@ -0,0 +1,289 @@
+package emulator
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContactSheetColumns is the number of columns of the contact sheet saved on
+// Close
+var ContactSheetColumns = 8
+
+// Headless is an emulator without window, every rendered frame is kept in
+// memory and can be saved as PNG files, an animated GIF or a contact sheet,
+// drawn with the same look as the windowed Emulator. Useful to run the
+// examples or the tests in machines without display or matrix.
+type Headless struct {
+	PixelPitch  int
+	Gutter      int
+	Width       int
+	Height      int
+	GutterColor color.Color
+	Margin      int
+	// Panel simulates the colors shown by a real panel, exact colors if nil
+	Panel *Panel
+	// RoundLEDs draws round LEDs instead of squares
+	RoundLEDs bool
+	// Glow is the radius in pixels of the light diffused around the LEDs
+	Glow int
+	// MaxFrames is the max number of frames kept, the oldest frames are
+	// dropped when exceeded, unlimited if zero
+	MaxFrames int
+	// Now returns the time of the rendered frames, time.Now if nil
+	Now func() time.Time
+	// Output is the path where the frames are saved on Close: an animated
+	// GIF if the extension is .gif, a contact sheet if .png, or a PNG per
+	// frame if the path has a verb for the index of the frame, as in
+	// "frame-%04d.png". Nothing is saved if empty.
+	Output string
+
+	m      sync.Mutex
+	leds   []color.Color
+	frames []Frame
+}
+
+// Frame is a frame rendered by a Headless emulator
+type Frame struct {
+	// LEDs are the colors of the leds, as given to Apply
+	LEDs []color.Color
+	// Time when the frame was rendered
+	Time time.Time
+}
+
+// NewHeadless returns a new Headless emulator of w x h leds, drawn with the
+// given pixelPitch, in the same proportion to the gutter as NewEmulator
+func NewHeadless(w, h, pixelPitch int) *Headless {
+	e := NewEmulator(w, h, pixelPitch, false)
+	return &Headless{
+		PixelPitch:  e.PixelPitch,
+		Gutter:      e.Gutter,
+		Width:       w,
+		Height:      h,
+		GutterColor: e.GutterColor,
+		Margin:      e.Margin,
+		leds:        make([]color.Color, w*h),
+	}
+}
+
+func (e *Headless) Geometry() (width, height int) {
+	return e.Width, e.Height
+}
+
+// Apply records a new frame with the given leds
+func (e *Headless) Apply(leds []color.Color) error {
+	if len(leds) != e.Width*e.Height {
+		return fmt.Errorf("emulator: invalid number of leds %d, expected %d", len(leds), e.Width*e.Height)
+	}
+
+	f := Frame{LEDs: make([]color.Color, len(leds)), Time: e.now()}
+	for i, c := range leds {
+		if c == nil {
+			c = color.Black
+		}
+
+		f.LEDs[i] = color.RGBAModel.Convert(c)
+	}
+
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.frames = append(e.frames, f)
+	if e.MaxFrames > 0 && len(e.frames) > e.MaxFrames {
+		e.frames = append(e.frames[:0], e.frames[len(e.frames)-e.MaxFrames:]...)
+	}
+
+	return nil
+}
+
+// Render records a new frame with the current leds, and sets all of them to
+// black, as the windowed Emulator does
+func (e *Headless) Render() error {
+	defer func() { e.leds = make([]color.Color, e.Height*e.Width) }()
+	return e.Apply(e.leds)
+}
+
+func (e *Headless) At(position int) color.Color {
+	if e.leds[position] == nil {
+		return color.Black
+	}
+
+	return e.leds[position]
+}
+
+func (e *Headless) Set(position int, c color.Color) {
+	e.leds[position] = color.RGBAModel.Convert(c)
+}
+
+// Close saves the frames at Output, if any
+func (e *Headless) Close() error {
+	switch {
+	case e.Output == "":
+		return nil
+	case strings.Contains(e.Output, "%"):
+		return e.WritePNGs(e.Output)
+	case strings.EqualFold(filepath.Ext(e.Output), ".gif"):
+		return e.WriteGIFFile(e.Output)
+	case strings.EqualFold(filepath.Ext(e.Output), ".png"):
+		return e.WriteContactSheet(e.Output, ContactSheetColumns)
+	default:
+		return fmt.Errorf("emulator: unsupported output %q, expected .gif or .png", e.Output)
+	}
+}
+
+// Frames returns the frames rendered so far
+func (e *Headless) Frames() []Frame {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	return append([]Frame(nil), e.frames...)
+}
+
+// Reset drops all the frames rendered so far
+func (e *Headless) Reset() {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.frames = nil
+}
+
+// Image returns the frame f drawn as in the windowed Emulator
+func (e *Headless) Image(f Frame) *image.RGBA {
+	l := e.layout()
+	img := image.NewRGBA(l.matrixWithMarginsRect())
+	l.draw(img, f.LEDs, e.GutterColor)
+	return img
+}
+
+// WritePNGs saves every frame as a PNG file, the name of the files is the
+// result of formatting pattern with the index of the frame, as in
+// "frame-%04d.png"
+func (e *Headless) WritePNGs(pattern string) error {
+	for i, f := range e.Frames() {
+		if err := writePNG(fmt.Sprintf(pattern, i), e.Image(f)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteGIF encodes all the frames as an animated GIF, looping forever. Every
+// frame is shown the time elapsed until the next one was rendered, the last
+// one is shown a second.
+func (e *Headless) WriteGIF(w io.Writer) error {
+	frames := e.Frames()
+	if len(frames) == 0 {
+		return fmt.Errorf("emulator: no frames rendered")
+	}
+
+	g := &gif.GIF{}
+	for i, f := range frames {
+		img := e.Image(f)
+		p := image.NewPaletted(img.Bounds(), palette.Plan9)
+		draw.Draw(p, p.Rect, img, img.Rect.Min, draw.Src)
+
+		d := time.Second
+		if i+1 < len(frames) {
+			d = frames[i+1].Time.Sub(f.Time)
+		}
+
+		g.Image = append(g.Image, p)
+		g.Delay = append(g.Delay, gifDelay(d))
+	}
+
+	return gif.EncodeAll(w, g)
+}
+
+// WriteGIFFile saves all the frames as an animated GIF at the given path
+func (e *Headless) WriteGIFFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := e.WriteGIF(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// ContactSheet returns all the frames in a single image, in rows of the given
+// number of columns
+func (e *Headless) ContactSheet(columns int) *image.RGBA {
+	frames := e.Frames()
+	if columns <= 0 {
+		columns = 1
+	}
+
+	size := e.layout().matrixWithMarginsRect().Size()
+	rows := (len(frames) + columns - 1) / columns
+	if len(frames) < columns {
+		columns = len(frames)
+	}
+
+	sheet := image.NewRGBA(image.Rect(0, 0, size.X*columns, size.Y*rows))
+	for i, f := range frames {
+		at := image.Pt(i%columns*size.X, i/columns*size.Y)
+		draw.Draw(sheet, image.Rectangle{at, at.Add(size)}, e.Image(f), image.Point{}, draw.Src)
+	}
+
+	return sheet
+}
+
+// WriteContactSheet saves the ContactSheet of the frames as a PNG file
+func (e *Headless) WriteContactSheet(path string, columns int) error {
+	return writePNG(path, e.ContactSheet(columns))
+}
+
+func (e *Headless) layout() layout {
+	return layout{
+		width: e.Width, height: e.Height,
+		pitch: e.PixelPitch, gutter: e.Gutter, margin: e.Margin,
+		panel: e.Panel, round: e.RoundLEDs, glow: e.Glow,
+	}
+}
+
+func (e *Headless) now() time.Time {
+	if e.Now == nil {
+		return time.Now()
+	}
+
+	return e.Now()
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// gifDelay returns d in hundredths of second, as used by the GIF delays
+func gifDelay(d time.Duration) int {
+	cs := int(d / (10 * time.Millisecond))
+	if cs < 1 {
+		return 1
+	}
+
+	return cs
+}
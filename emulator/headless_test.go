@@ -0,0 +1,158 @@
+package emulator
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type HeadlessSuite struct{}
+
+var _ = Suite(&HeadlessSuite{})
+
+var red = color.RGBA{255, 0, 0, 255}
+
+func (s *HeadlessSuite) TestRender(c *C) {
+	e := NewHeadless(2, 2, 12)
+	e.Set(3, red)
+	c.Assert(e.Render(), IsNil)
+	c.Assert(e.Render(), IsNil)
+
+	frames := e.Frames()
+	c.Assert(frames, HasLen, 2)
+	c.Assert(frames[0].LEDs[3], Equals, color.Color(red))
+	c.Assert(frames[0].LEDs[0], Equals, color.Color(color.RGBAModel.Convert(color.Black)))
+	c.Assert(frames[1].LEDs[3], Equals, color.Color(color.RGBAModel.Convert(color.Black)))
+}
+
+func (s *HeadlessSuite) TestImage(c *C) {
+	e := NewHeadless(2, 2, 12)
+	e.Set(3, red)
+	c.Assert(e.Render(), IsNil)
+
+	img := e.Image(e.Frames()[0])
+	// 2 leds of 12 pixels, a gutter of 6 and the margins of 10
+	c.Assert(img.Bounds(), Equals, image.Rect(0, 0, 50, 50))
+	c.Assert(img.At(0, 0), Equals, color.Color(color.RGBA{20, 20, 20, 255}))
+	c.Assert(img.At(10, 10), Equals, color.Color(color.RGBA{0, 0, 0, 255}))
+	c.Assert(img.At(25, 25), Equals, color.Color(color.RGBA{20, 20, 20, 255}))
+	c.Assert(img.At(39, 39), Equals, color.Color(red))
+}
+
+func (s *HeadlessSuite) TestMaxFrames(c *C) {
+	e := NewHeadless(1, 1, 2)
+	e.MaxFrames = 2
+	for _, v := range []uint8{1, 2, 3} {
+		e.Set(0, color.RGBA{v, 0, 0, 255})
+		c.Assert(e.Render(), IsNil)
+	}
+
+	frames := e.Frames()
+	c.Assert(frames, HasLen, 2)
+	c.Assert(frames[0].LEDs[0], Equals, color.Color(color.RGBA{2, 0, 0, 255}))
+}
+
+func (s *HeadlessSuite) TestWriteGIF(c *C) {
+	now := time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
+	e := NewHeadless(2, 2, 4)
+	e.Now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		c.Assert(e.Render(), IsNil)
+		now = now.Add(100 * time.Millisecond)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	c.Assert(e.WriteGIF(buf), IsNil)
+
+	g, err := gif.DecodeAll(buf)
+	c.Assert(err, IsNil)
+	c.Assert(g.Image, HasLen, 3)
+	c.Assert(g.Delay, DeepEquals, []int{10, 10, 100})
+}
+
+func (s *HeadlessSuite) TestWriteGIFEmpty(c *C) {
+	e := NewHeadless(2, 2, 4)
+	c.Assert(e.WriteGIF(bytes.NewBuffer(nil)), ErrorMatches, ".*no frames.*")
+}
+
+func (s *HeadlessSuite) TestContactSheet(c *C) {
+	e := NewHeadless(2, 2, 12)
+	for i := 0; i < 3; i++ {
+		c.Assert(e.Render(), IsNil)
+	}
+
+	c.Assert(e.ContactSheet(2).Bounds(), Equals, image.Rect(0, 0, 100, 100))
+	c.Assert(e.ContactSheet(5).Bounds(), Equals, image.Rect(0, 0, 150, 50))
+}
+
+func (s *HeadlessSuite) TestWritePNGs(c *C) {
+	dir := c.MkDir()
+	e := NewHeadless(2, 2, 4)
+	c.Assert(e.Render(), IsNil)
+	c.Assert(e.Render(), IsNil)
+	c.Assert(e.WritePNGs(filepath.Join(dir, "frame-%02d.png")), IsNil)
+
+	for _, name := range []string{"frame-00.png", "frame-01.png"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		c.Assert(err, IsNil)
+	}
+}
+
+func (s *HeadlessSuite) TestRoundLEDs(c *C) {
+	e := NewHeadless(1, 1, 12)
+	e.Margin, e.RoundLEDs = 2, true
+	e.Set(0, red)
+	c.Assert(e.Render(), IsNil)
+
+	img := e.Image(e.Frames()[0])
+	c.Assert(img.At(8, 8), Equals, color.Color(red))
+	// the corners of the led show the gutter
+	c.Assert(img.At(2, 2), Equals, color.Color(color.RGBA{20, 20, 20, 255}))
+}
+
+func (s *HeadlessSuite) TestGlow(c *C) {
+	e := NewHeadless(1, 1, 12)
+	e.Margin, e.Glow = 4, 4
+	e.Set(0, red)
+	c.Assert(e.Render(), IsNil)
+
+	img := e.Image(e.Frames()[0])
+	c.Assert(img.At(8, 8), Equals, color.Color(red))
+
+	// the glow fades with the distance to the led
+	near := img.RGBAAt(3, 8)
+	far := img.RGBAAt(1, 8)
+	c.Assert(near.R > far.R, Equals, true)
+	c.Assert(far.R > 20, Equals, true)
+	c.Assert(near.G, Equals, uint8(20))
+	c.Assert(img.RGBAAt(0, 0), Equals, color.RGBA{20, 20, 20, 255})
+}
+
+func (s *HeadlessSuite) TestCloseOutput(c *C) {
+	dir := c.MkDir()
+	for _, output := range []string{"out.gif", "sheet.png", "frame-%d.png"} {
+		e := NewHeadless(2, 2, 4)
+		e.Output = filepath.Join(dir, output)
+		c.Assert(e.Render(), IsNil)
+		c.Assert(e.Close(), IsNil)
+	}
+
+	for _, name := range []string{"out.gif", "sheet.png", "frame-0.png"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		c.Assert(err, IsNil)
+	}
+
+	e := NewHeadless(2, 2, 4)
+	e.Output = filepath.Join(dir, "out.bmp")
+	c.Assert(e.Close(), ErrorMatches, ".*unsupported output.*")
+}
@@ -0,0 +1,129 @@
+package emulator
+
+import (
+	"image"
+	"time"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix/input"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+)
+
+var keys = map[key.Code]input.Key{
+	key.CodeUpArrow:         input.KeyUp,
+	key.CodeDownArrow:       input.KeyDown,
+	key.CodeLeftArrow:       input.KeyLeft,
+	key.CodeRightArrow:      input.KeyRight,
+	key.CodeReturnEnter:     input.KeyEnter,
+	key.CodeKeypadEnter:     input.KeyEnter,
+	key.CodeEscape:          input.KeyEscape,
+	key.CodeDeleteBackspace: input.KeyBackspace,
+	key.CodeDeleteForward:   input.KeyDelete,
+	key.CodeTab:             input.KeyTab,
+	key.CodeHome:            input.KeyHome,
+	key.CodeEnd:             input.KeyEnd,
+	key.CodePageUp:          input.KeyPageUp,
+	key.CodePageDown:        input.KeyPageDown,
+}
+
+var buttons = map[mouse.Button]input.Button{
+	mouse.ButtonLeft:       input.ButtonLeft,
+	mouse.ButtonMiddle:     input.ButtonMiddle,
+	mouse.ButtonRight:      input.ButtonRight,
+	mouse.ButtonWheelUp:    input.WheelUp,
+	mouse.ButtonWheelDown:  input.WheelDown,
+	mouse.ButtonWheelLeft:  input.WheelLeft,
+	mouse.ButtonWheelRight: input.WheelRight,
+}
+
+// Events returns the channel receiving the key and mouse events of the window
+func (e *Emulator) Events() <-chan input.Event {
+	return e.input.Events()
+}
+
+// Handle sets a function called with every key and mouse event of the window,
+// from the goroutine of the window, so it should not block
+func (e *Emulator) Handle(f input.Handler) {
+	e.input.Handle(f)
+}
+
+// keyEvent returns the input.Event of a key event
+func keyEvent(k key.Event) input.Event {
+	ev := input.Event{
+		Type:      input.KeyPress,
+		Key:       keys[k.Code],
+		Modifiers: modifiers(k.Modifiers),
+		Time:      time.Now(),
+	}
+
+	if k.Direction == key.DirRelease {
+		ev.Type = input.KeyRelease
+	}
+
+	switch {
+	case k.Code >= key.CodeF1 && k.Code <= key.CodeF12:
+		ev.Key = input.KeyF1 + input.Key(k.Code-key.CodeF1)
+	case ev.Key == input.KeyUnknown && k.Rune >= 0:
+		ev.Key, ev.Rune = input.KeyRune, k.Rune
+	}
+
+	return ev
+}
+
+// mouseEvent returns the input.Event of a mouse event, in the coordinates of
+// the LEDs of l, false if the event should be ignored: the mouse moves within
+// the same LED and the events out of the matrix. Releases are always
+// delivered, at the closest LED, so every press is followed by a release
+func mouseEvent(m mouse.Event, l layout, last image.Point) (input.Event, bool) {
+	ev := input.Event{
+		Button:    buttons[m.Button],
+		Modifiers: modifiers(m.Modifiers),
+		Time:      time.Now(),
+	}
+
+	p, ok := l.ledAt(int(m.X), int(m.Y))
+	if !ok {
+		if m.Direction != mouse.DirRelease || m.Button.IsWheel() {
+			return ev, false
+		}
+		p = l.nearestLED(int(m.X), int(m.Y))
+	}
+
+	ev.X, ev.Y = p.X, p.Y
+	switch {
+	case m.Button.IsWheel():
+		ev.Type = input.MouseWheel
+	case m.Direction == mouse.DirPress:
+		ev.Type = input.MousePress
+	case m.Direction == mouse.DirRelease:
+		ev.Type = input.MouseRelease
+	default:
+		ev.Type = input.MouseMove
+		if p == last {
+			return ev, false
+		}
+	}
+
+	return ev, true
+}
+
+func modifiers(m key.Modifiers) input.Modifiers {
+	var mod input.Modifiers
+	if m&key.ModShift != 0 {
+		mod |= input.Shift
+	}
+
+	if m&key.ModControl != 0 {
+		mod |= input.Control
+	}
+
+	if m&key.ModAlt != 0 {
+		mod |= input.Alt
+	}
+
+	if m&key.ModMeta != 0 {
+		mod |= input.Meta
+	}
+
+	return mod
+}
@@ -0,0 +1,75 @@
+package emulator
+
+import (
+	"image"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix/input"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+	. "gopkg.in/check.v1"
+)
+
+type InputSuite struct{}
+
+var _ = Suite(&InputSuite{})
+
+func (s *InputSuite) TestKeyEvent(c *C) {
+	ev := keyEvent(key.Event{Rune: 'a', Code: key.CodeA, Direction: key.DirPress, Modifiers: key.ModShift})
+	c.Assert(ev.Type, Equals, input.KeyPress)
+	c.Assert(ev.Key, Equals, input.KeyRune)
+	c.Assert(ev.Rune, Equals, 'a')
+	c.Assert(ev.Modifiers, Equals, input.Shift)
+
+	ev = keyEvent(key.Event{Rune: -1, Code: key.CodeUpArrow, Direction: key.DirRelease})
+	c.Assert(ev.Type, Equals, input.KeyRelease)
+	c.Assert(ev.Key, Equals, input.KeyUp)
+
+	// the repetitions of a key held are presses
+	ev = keyEvent(key.Event{Rune: -1, Code: key.CodeF5, Direction: key.DirNone})
+	c.Assert(ev.Type, Equals, input.KeyPress)
+	c.Assert(ev.Key, Equals, input.KeyF5)
+}
+
+func (s *InputSuite) TestMouseEvent(c *C) {
+	// leds of 12 pixels with a gutter of 6 and margins of 10
+	l := NewHeadless(4, 2, 12).layout()
+
+	ev, ok := mouseEvent(mouse.Event{X: 30, Y: 12, Button: mouse.ButtonLeft, Direction: mouse.DirPress}, l, image.Point{})
+	c.Assert(ok, Equals, true)
+	c.Assert(ev.Type, Equals, input.MousePress)
+	c.Assert(ev.Button, Equals, input.ButtonLeft)
+	c.Assert(image.Pt(ev.X, ev.Y), Equals, image.Pt(1, 0))
+
+	_, ok = mouseEvent(mouse.Event{X: 30, Y: 12}, l, image.Pt(1, 0))
+	c.Assert(ok, Equals, false)
+
+	ev, ok = mouseEvent(mouse.Event{X: 30, Y: 30}, l, image.Pt(1, 0))
+	c.Assert(ok, Equals, true)
+	c.Assert(ev.Type, Equals, input.MouseMove)
+	c.Assert(image.Pt(ev.X, ev.Y), Equals, image.Pt(1, 1))
+
+	ev, ok = mouseEvent(mouse.Event{X: 30, Y: 30, Button: mouse.ButtonWheelUp, Direction: mouse.DirStep}, l, image.Pt(1, 1))
+	c.Assert(ok, Equals, true)
+	c.Assert(ev.Type, Equals, input.MouseWheel)
+	c.Assert(ev.Button, Equals, input.WheelUp)
+
+	_, ok = mouseEvent(mouse.Event{X: 5, Y: 30}, l, image.Pt(1, 1))
+	c.Assert(ok, Equals, false)
+	_, ok = mouseEvent(mouse.Event{X: 30, Y: 50}, l, image.Pt(1, 1))
+	c.Assert(ok, Equals, false)
+
+	ev, ok = mouseEvent(mouse.Event{X: 100, Y: 5, Button: mouse.ButtonLeft, Direction: mouse.DirRelease}, l, image.Pt(1, 1))
+	c.Assert(ok, Equals, true)
+	c.Assert(ev.Type, Equals, input.MouseRelease)
+	c.Assert(image.Pt(ev.X, ev.Y), Equals, image.Pt(3, 0))
+}
+
+func (s *InputSuite) TestEvents(c *C) {
+	e := NewEmulator(2, 2, 12, false)
+	var handled []input.Event
+	e.Handle(func(ev input.Event) { handled = append(handled, ev) })
+
+	e.input.Push(input.Event{Type: input.KeyPress, Key: input.KeyEnter})
+	c.Assert(handled, HasLen, 1)
+	c.Assert((<-e.Events()).Key, Equals, input.KeyEnter)
+}
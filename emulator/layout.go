@@ -0,0 +1,198 @@
+package emulator
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// glowIntensity is the intensity of the glow next to the LED, relative to the
+// color of the LED
+const glowIntensity = 0.4
+
+// layout is how an emulated matrix is drawn on screen, shared by the windowed
+// and the headless emulators so both look the same
+type layout struct {
+	width, height int
+	pitch, gutter int
+	margin        int
+
+	// panel maps the colors of the leds, exact colors if nil
+	panel *Panel
+	// round draws round leds instead of squares
+	round bool
+	// glow is the radius in pixels of the light diffused around the leds
+	glow int
+}
+
+// matrixWithMarginsRect Returns a Rectangle that describes entire emulated RGB Matrix, including margins.
+func (l layout) matrixWithMarginsRect() image.Rectangle {
+	upperLeftLED := l.ledRect(0, 0)
+	lowerRightLED := l.ledRect(l.width-1, l.height-1)
+	return image.Rect(upperLeftLED.Min.X-l.margin, upperLeftLED.Min.Y-l.margin, lowerRightLED.Max.X+l.margin, lowerRightLED.Max.Y+l.margin)
+}
+
+// ledRect Returns a Rectangle for the LED at col and row.
+func (l layout) ledRect(col int, row int) image.Rectangle {
+	x := (col * (l.pitch + l.gutter)) + l.margin
+	y := (row * (l.pitch + l.gutter)) + l.margin
+	return image.Rect(x, y, x+l.pitch, y+l.pitch)
+}
+
+// ledAt returns the column and row of the LED at the pixel (x, y), the pixels
+// of the gutter belong to the LED at their left and top, false if the pixel is
+// out of the LEDs
+func (l layout) ledAt(x, y int) (image.Point, bool) {
+	step := l.pitch + l.gutter
+	if step <= 0 || x < l.margin || y < l.margin {
+		return image.Point{}, false
+	}
+
+	p := image.Pt((x-l.margin)/step, (y-l.margin)/step)
+	if p.X >= l.width || p.Y >= l.height {
+		return image.Point{}, false
+	}
+
+	return p, true
+}
+
+// nearestLED returns the column and row of the LED at the pixel (x, y), or of
+// the closest LED if the pixel is out of the LEDs
+func (l layout) nearestLED(x, y int) image.Point {
+	step := l.pitch + l.gutter
+	if step <= 0 || l.width <= 0 || l.height <= 0 {
+		return image.Point{}
+	}
+
+	p := image.Pt((x-l.margin)/step, (y-l.margin)/step)
+	p.X = clamp(p.X, 0, l.width-1)
+	p.Y = clamp(p.Y, 0, l.height-1)
+	return p
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// squares returns true if the leds can be drawn as filled rectangles
+func (l layout) squares() bool {
+	return !l.round && l.glow <= 0
+}
+
+// color returns the color shown for c
+func (l layout) color(c color.Color) color.Color {
+	if c == nil {
+		c = color.Black
+	}
+
+	if l.panel == nil {
+		return c
+	}
+
+	return l.panel.Color(c)
+}
+
+// draw draws the matrix with the given leds over dst, the gutter and the
+// margins are filled with gutter, nil leds are drawn black
+func (l layout) draw(dst *image.RGBA, leds []color.Color, gutter color.Color) {
+	draw.Draw(dst, l.matrixWithMarginsRect(), image.NewUniform(gutter), image.Point{}, draw.Src)
+
+	if l.squares() {
+		c := &image.Uniform{}
+		for row := 0; row < l.height; row++ {
+			for col := 0; col < l.width; col++ {
+				c.C = l.color(leds[col+(row*l.width)])
+				draw.Draw(dst, l.ledRect(col, row), c, image.Point{}, draw.Over)
+			}
+		}
+
+		return
+	}
+
+	colors := make([]color.RGBA, len(leds))
+	for i, c := range leds {
+		colors[i] = color.RGBAModel.Convert(l.color(c)).(color.RGBA)
+	}
+
+	// the glow of the leds is added before the leds, so it's only visible
+	// over the gutter
+	if l.glow > 0 {
+		for i, c := range colors {
+			l.drawLED(dst, i%l.width, i/l.width, c, l.addGlow)
+		}
+	}
+
+	for i, c := range colors {
+		l.drawLED(dst, i%l.width, i/l.width, c, l.blendLED)
+	}
+}
+
+// drawLED calls f with every pixel of dst close to the led at col and row, and
+// its distance to the shape of the led
+func (l layout) drawLED(dst *image.RGBA, col, row int, c color.RGBA, f func(p []uint8, c color.RGBA, d float64)) {
+	r := l.ledRect(col, row)
+	area := r.Inset(-l.glow).Intersect(dst.Rect)
+
+	cx, cy := float64(r.Min.X+r.Max.X)/2, float64(r.Min.Y+r.Max.Y)/2
+	radius := float64(l.pitch) / 2
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			dx := math.Abs(float64(x)+0.5-cx) - radius
+			dy := math.Abs(float64(y)+0.5-cy) - radius
+
+			// distance to the border of the led, negative inside
+			var d float64
+			if l.round {
+				d = math.Hypot(dx+radius, dy+radius) - radius
+			} else {
+				d = math.Hypot(math.Max(dx, 0), math.Max(dy, 0)) + math.Min(math.Max(dx, dy), 0)
+			}
+
+			i := dst.PixOffset(x, y)
+			f(dst.Pix[i:i+4], c, d)
+		}
+	}
+}
+
+// blendLED draws the led over the pixel p, antialiasing the border
+func (l layout) blendLED(p []uint8, c color.RGBA, d float64) {
+	a := 0.5 - d
+	if a <= 0 {
+		return
+	}
+
+	if a > 1 {
+		a = 1
+	}
+
+	for i, v := range []uint8{c.R, c.G, c.B} {
+		p[i] = uint8(float64(p[i])*(1-a) + float64(v)*a + 0.5)
+	}
+
+	p[3] = 255
+}
+
+// addGlow adds to the pixel p the light diffused by the led, decreasing with
+// the distance to the led
+func (l layout) addGlow(p []uint8, c color.RGBA, d float64) {
+	if d >= float64(l.glow) {
+		return
+	}
+
+	if d < 0 {
+		d = 0
+	}
+
+	a := 1 - d/float64(l.glow)
+	a = glowIntensity * a * a
+	for i, v := range []uint8{c.R, c.G, c.B} {
+		p[i] = uint8(math.Min(float64(p[i])+float64(v)*a, 255))
+	}
+}
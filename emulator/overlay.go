@@ -0,0 +1,89 @@
+package emulator
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// overlayBackground is the color behind the text of the overlay
+var overlayBackground = color.RGBA{0, 0, 0, 192}
+
+// stats measures the frames drawn per second and the time drawing them
+type stats struct {
+	start  time.Time
+	frames int
+	fps    float64
+	draw   time.Duration
+}
+
+// frame records a frame drawn at now, taking d
+func (s *stats) frame(now time.Time, d time.Duration) {
+	s.draw = d
+	if s.start.IsZero() {
+		s.start = now
+		return
+	}
+
+	s.frames++
+	if elapsed := now.Sub(s.start); elapsed >= time.Second {
+		s.fps = float64(s.frames) / elapsed.Seconds()
+		s.start, s.frames = now, 0
+	}
+}
+
+// overlay returns the lines of the overlay, the stats and the LED under the
+// mouse, if any
+func (e *Emulator) overlay() []string {
+	lines := []string{fmt.Sprintf("%.1f fps %s", e.stats.fps, e.stats.draw.Round(10*time.Microsecond))}
+	if !e.hovered {
+		return lines
+	}
+
+	p := e.hover
+	c := color.RGBAModel.Convert(e.color(p.X + p.Y*e.Width)).(color.RGBA)
+	return append(lines, fmt.Sprintf("%d,%d #%02x%02x%02x", p.X, p.Y, c.R, c.G, c.B))
+}
+
+// color returns the color of the LED at position in the last frame
+func (e *Emulator) color(position int) color.Color {
+	if e.last[position] == nil {
+		return color.Black
+	}
+
+	return e.last[position]
+}
+
+// drawOverlay draws the lines over the top left corner of dst
+func drawOverlay(dst draw.Image, lines []string) {
+	drawText(dst, dst.Bounds().Min, lines)
+}
+
+// drawText draws the lines at the given point, over a dark background
+func drawText(dst draw.Image, at image.Point, lines []string) {
+	face := basicfont.Face7x13
+	m := face.Metrics()
+	height := m.Height.Ceil()
+
+	var width int
+	for _, l := range lines {
+		if w := font.MeasureString(face, l).Ceil(); w > width {
+			width = w
+		}
+	}
+
+	r := image.Rect(0, 0, width+4, height*len(lines)+4).Add(at)
+	draw.Draw(dst, r, image.NewUniform(overlayBackground), image.Point{}, draw.Over)
+
+	d := &font.Drawer{Dst: dst, Src: image.White, Face: face}
+	for i, l := range lines {
+		d.Dot = fixed.P(r.Min.X+2, r.Min.Y+2+height*i+m.Ascent.Ceil())
+		d.DrawString(l)
+	}
+}
@@ -0,0 +1,120 @@
+package emulator
+
+import (
+	"image/color"
+	"math"
+	"sync"
+)
+
+// bitPlanes is the max number of PWM bits supported by the C library, the
+// colors are mapped to values of this number of bits
+const bitPlanes = 11
+
+// Panel simulates how a real panel shows the colors, following what the C
+// library does before lighting the LEDs: the colors are corrected to the
+// luminance perceived, dimmed by the brightness and quantized to the PWM bits.
+// The result is the light emitted, converted to sRGB to be shown on screen.
+type Panel struct {
+	// PWMBits used for output, as in HardwareConfig, 11 if zero
+	PWMBits int
+	// Brightness of the panel in percent, 100 if zero
+	Brightness int
+	// LuminanceCorrect maps the colors with the CIE1931 luminance correction,
+	// as the C library does by default
+	LuminanceCorrect bool
+	// InverseColors inverts the light emitted, as in HardwareConfig
+	InverseColors bool
+
+	m     sync.Mutex
+	key   panelKey
+	table *[256]uint8
+}
+
+// panelKey are the values of a Panel used to build its lookup table
+type panelKey struct {
+	pwmBits, brightness      int
+	luminance, inverseColors bool
+}
+
+// NewPanel returns a Panel with the defaults of the C library
+func NewPanel() *Panel {
+	return &Panel{PWMBits: bitPlanes, Brightness: 100, LuminanceCorrect: true}
+}
+
+// Color returns the color shown by the panel when c is set
+func (p *Panel) Color(c color.Color) color.RGBA {
+	t := p.lookup()
+	r, g, b, _ := c.RGBA()
+	return color.RGBA{t[r>>8], t[g>>8], t[b>>8], 255}
+}
+
+// lookup returns the table mapping every channel value to the value shown,
+// built again if the Panel changed
+func (p *Panel) lookup() *[256]uint8 {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	key := panelKey{p.PWMBits, p.Brightness, p.LuminanceCorrect, p.InverseColors}
+	if p.table != nil && p.key == key {
+		return p.table
+	}
+
+	p.key, p.table = key, &[256]uint8{}
+	for i := range p.table {
+		p.table[i] = p.channel(uint8(i))
+	}
+
+	return p.table
+}
+
+// channel returns the value shown for the value v of a channel
+func (p *Panel) channel(v uint8) uint8 {
+	bits := p.PWMBits
+	if bits <= 0 || bits > bitPlanes {
+		bits = bitPlanes
+	}
+
+	brightness := p.Brightness
+	if brightness <= 0 || brightness > 100 {
+		brightness = 100
+	}
+
+	var out int
+	if p.LuminanceCorrect {
+		out = luminanceCIE1931(v, brightness)
+	} else {
+		out = (int(v) * brightness / 100) << (bitPlanes - 8)
+	}
+
+	if p.InverseColors {
+		out = (1<<bitPlanes - 1) - out
+	}
+
+	// only the most significant PWM bits are shown, during the whole refresh
+	// cycle, so the max value is still fully lit
+	out >>= uint(bitPlanes - bits)
+	return linearToSRGB(float64(out) / float64(int(1)<<uint(bits)-1))
+}
+
+// luminanceCIE1931 returns the PWM value of v, as the C library does
+func luminanceCIE1931(v uint8, brightness int) int {
+	l := float64(v) * float64(brightness) / 255
+	if l <= 8 {
+		l = l / 902.3
+	} else {
+		l = math.Pow((l+16)/116, 3)
+	}
+
+	return int(math.Floor((1<<bitPlanes-1)*l + 0.5))
+}
+
+// linearToSRGB returns the 8 bits sRGB value of a linear light intensity
+func linearToSRGB(l float64) uint8 {
+	if l <= 0.0031308 {
+		l *= 12.92
+	} else {
+		l = 1.055*math.Pow(l, 1/2.4) - 0.055
+	}
+
+	return uint8(math.Floor(l*255 + 0.5))
+}
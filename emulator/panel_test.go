@@ -0,0 +1,57 @@
+package emulator
+
+import (
+	"image/color"
+
+	. "gopkg.in/check.v1"
+)
+
+type PanelSuite struct{}
+
+var _ = Suite(&PanelSuite{})
+
+func (s *PanelSuite) TestColor(c *C) {
+	p := NewPanel()
+	c.Assert(p.Color(color.Black), Equals, color.RGBA{0, 0, 0, 255})
+	c.Assert(p.Color(color.White), Equals, color.RGBA{255, 255, 255, 255})
+
+	// the luminance correction shows the mid grays close to the sRGB ones
+	mid := p.Color(color.RGBA{128, 128, 128, 255})
+	c.Assert(mid.R > 118 && mid.R < 138, Equals, true)
+}
+
+func (s *PanelSuite) TestBrightness(c *C) {
+	p := NewPanel()
+	p.Brightness = 50
+	c.Assert(p.Color(color.White).R < 200, Equals, true)
+	c.Assert(p.Color(color.White).R > 100, Equals, true)
+}
+
+func (s *PanelSuite) TestPWMBits(c *C) {
+	p := NewPanel()
+	p.PWMBits = 1
+	for v := 0; v < 256; v++ {
+		got := p.Color(color.RGBA{uint8(v), 0, 0, 255}).R
+		c.Assert(got == 0 || got == 255, Equals, true, Commentf("%d -> %d", v, got))
+	}
+
+	// with less bits the dark colors are shown black
+	p.PWMBits = 4
+	c.Assert(p.Color(color.RGBA{20, 20, 20, 255}), Equals, color.RGBA{0, 0, 0, 255})
+}
+
+func (s *PanelSuite) TestInverseColors(c *C) {
+	p := NewPanel()
+	p.InverseColors = true
+	c.Assert(p.Color(color.Black), Equals, color.RGBA{255, 255, 255, 255})
+	c.Assert(p.Color(color.White), Equals, color.RGBA{0, 0, 0, 255})
+}
+
+func (s *PanelSuite) TestWithoutLuminanceCorrect(c *C) {
+	p := NewPanel()
+	p.LuminanceCorrect = false
+
+	// the light is linear with the value, so the mid grays look brighter
+	mid := p.Color(color.RGBA{128, 128, 128, 255})
+	c.Assert(mid.R > 170, Equals, true)
+}
@@ -0,0 +1,38 @@
+package emulator
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Screenshot returns the last frame drawn, as shown in the window
+func (e *Emulator) Screenshot() *image.RGBA {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	l := e.layout()
+	img := image.NewRGBA(l.matrixWithMarginsRect())
+	l.draw(img, e.last, e.GutterColor)
+	return img
+}
+
+// SaveScreenshot saves the Screenshot as a PNG file at the given path
+func (e *Emulator) SaveScreenshot(path string) error {
+	return writePNG(path, e.Screenshot())
+}
+
+// saveScreenshot saves a screenshot in ScreenshotDir, named after the current
+// time, the result is reported in the standard error
+func (e *Emulator) saveScreenshot() {
+	name := fmt.Sprintf("emulator-%s.png", time.Now().Format("20060102-150405.000"))
+	path := filepath.Join(e.ScreenshotDir, name)
+	if err := e.SaveScreenshot(path); err != nil {
+		fmt.Fprintf(os.Stderr, "error saving screenshot: %s\n", err)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "screenshot saved at %s\n", path)
+}
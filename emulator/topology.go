@@ -0,0 +1,128 @@
+package emulator
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// topologyColor is the color of the outlines of the panels
+var topologyColor = color.RGBA{255, 200, 0, 255}
+
+// Topology is how the LEDs of the matrix are split in panels and wired, as
+// given by the HardwareConfig of the real matrix
+type Topology struct {
+	// Rows and Cols are the size of every panel
+	Rows, Cols int
+	// ChainLength is the number of panels daisy-chained in every output
+	ChainLength int
+	// Parallel is the number of chains connected in parallel
+	Parallel int
+	// Interlaced scans the rows interlaced instead of progressive
+	Interlaced bool
+}
+
+// PanelArea is a panel of a Topology
+type PanelArea struct {
+	// Bounds are the LEDs covered by the panel
+	Bounds image.Rectangle
+	// Chain is the position of the panel in the chain, starting with the one
+	// connected to the Pi
+	Chain int
+	// Parallel is the output the chain of the panel is connected to
+	Parallel int
+}
+
+// Panels returns all the panels of the matrix. The pixels are shifted from
+// the first column, so the first columns are shown by the last panel of the
+// chain and the panel connected to the Pi shows the last columns.
+func (t Topology) Panels() []PanelArea {
+	var panels []PanelArea
+	for p := 0; p < t.Parallel; p++ {
+		for i := 0; i < t.ChainLength; i++ {
+			panels = append(panels, PanelArea{
+				Bounds:   image.Rect(i*t.Cols, p*t.Rows, (i+1)*t.Cols, (p+1)*t.Rows),
+				Chain:    t.ChainLength - 1 - i,
+				Parallel: p,
+			})
+		}
+	}
+
+	return panels
+}
+
+// ScanSteps returns the number of steps needed to refresh a panel, the panels
+// light two rows at once, one in every half
+func (t Topology) ScanSteps() int {
+	if t.Rows < 2 {
+		return 1
+	}
+
+	return t.Rows / 2
+}
+
+// ScanStep returns the step in which the given row of the matrix is lit
+func (t Topology) ScanStep(row int) int {
+	steps := t.ScanSteps()
+	addr := row % t.Rows % steps
+	if !t.Interlaced {
+		return addr
+	}
+
+	// the even rows are scanned first, then the odd ones
+	if addr%2 == 0 {
+		return addr / 2
+	}
+
+	return (steps+1)/2 + addr/2
+}
+
+// drawTopology draws over dst the outline and the label of every panel, and a
+// mark in every row colored by the step in which is scanned, from red to blue
+func (l layout) drawTopology(dst draw.Image, t Topology) {
+	if t.Rows <= 0 || t.Cols <= 0 {
+		return
+	}
+
+	half := l.gutter / 2
+	for row := 0; row < l.height; row++ {
+		r := l.ledRect(0, row)
+		r = image.Rect(r.Min.X-half-2, r.Min.Y, r.Min.X-half, r.Max.Y)
+		draw.Draw(dst, r, image.NewUniform(scanColor(t.ScanStep(row), t.ScanSteps())), image.Point{}, draw.Src)
+	}
+
+	for _, p := range t.Panels() {
+		if p.Bounds.Max.X > l.width || p.Bounds.Max.Y > l.height {
+			continue
+		}
+
+		min := l.ledRect(p.Bounds.Min.X, p.Bounds.Min.Y).Min
+		max := l.ledRect(p.Bounds.Max.X-1, p.Bounds.Max.Y-1).Max
+		r := image.Rectangle{min, max}.Inset(-half)
+		drawOutline(dst, r, topologyColor)
+		drawText(dst, r.Min.Add(image.Pt(1, 1)), []string{
+			fmt.Sprintf("chain %d", p.Chain),
+			fmt.Sprintf("output %d", p.Parallel),
+		})
+	}
+}
+
+// scanColor returns the color of the step of a scan, from red to blue
+func scanColor(step, steps int) color.Color {
+	if steps <= 1 {
+		return color.RGBA{255, 0, 0, 255}
+	}
+
+	v := uint8(step * 255 / (steps - 1))
+	return color.RGBA{255 - v, 0, v, 255}
+}
+
+// drawOutline draws a line of one pixel around r
+func drawOutline(dst draw.Image, r image.Rectangle, c color.Color) {
+	u := image.NewUniform(c)
+	draw.Draw(dst, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+1), u, image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(r.Min.X, r.Max.Y-1, r.Max.X, r.Max.Y), u, image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(r.Min.X, r.Min.Y, r.Min.X+1, r.Max.Y), u, image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(r.Max.X-1, r.Min.Y, r.Max.X, r.Max.Y), u, image.Point{}, draw.Src)
+}
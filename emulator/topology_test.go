@@ -0,0 +1,56 @@
+package emulator
+
+import (
+	"image"
+	"image/color"
+
+	. "gopkg.in/check.v1"
+)
+
+type TopologySuite struct{}
+
+var _ = Suite(&TopologySuite{})
+
+func (s *TopologySuite) TestPanels(c *C) {
+	t := Topology{Rows: 16, Cols: 32, ChainLength: 2, Parallel: 2}
+	c.Assert(t.Panels(), DeepEquals, []PanelArea{
+		{Bounds: image.Rect(0, 0, 32, 16), Chain: 1, Parallel: 0},
+		{Bounds: image.Rect(32, 0, 64, 16), Chain: 0, Parallel: 0},
+		{Bounds: image.Rect(0, 16, 32, 32), Chain: 1, Parallel: 1},
+		{Bounds: image.Rect(32, 16, 64, 32), Chain: 0, Parallel: 1},
+	})
+}
+
+func (s *TopologySuite) TestScanStep(c *C) {
+	t := Topology{Rows: 8, Cols: 8, ChainLength: 1, Parallel: 2}
+	c.Assert(t.ScanSteps(), Equals, 4)
+
+	var steps []int
+	for row := 0; row < 16; row++ {
+		steps = append(steps, t.ScanStep(row))
+	}
+
+	c.Assert(steps, DeepEquals, []int{0, 1, 2, 3, 0, 1, 2, 3, 0, 1, 2, 3, 0, 1, 2, 3})
+
+	t.Interlaced = true
+	steps = steps[:0]
+	for row := 0; row < 8; row++ {
+		steps = append(steps, t.ScanStep(row))
+	}
+
+	c.Assert(steps, DeepEquals, []int{0, 2, 1, 3, 0, 2, 1, 3})
+}
+
+func (s *TopologySuite) TestDrawTopology(c *C) {
+	e := NewEmulator(4, 2, 12, false)
+	e.Topology = &Topology{Rows: 2, Cols: 2, ChainLength: 2, Parallel: 1}
+
+	without := e.Screenshot()
+	l := e.layout()
+	l.drawTopology(without, *e.Topology)
+
+	// the outline of the second panel is drawn in the gutter between panels
+	c.Assert(without.At(l.ledRect(2, 0).Min.X-3, 30), Equals, color.Color(topologyColor))
+	// and the scan mark of the second row at the left of the leds
+	c.Assert(without.At(l.ledRect(0, 1).Min.X-4, l.ledRect(0, 1).Min.Y+6), Equals, scanColor(0, 1))
+}
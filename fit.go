@@ -0,0 +1,371 @@
+package rgbmatrix
+
+import (
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sync"
+)
+
+// FitMode defines how an image is adjusted to the size of the Canvas
+type FitMode int
+
+const (
+	// FitNone draws the image as is, from the top-left corner
+	FitNone FitMode = iota
+	// FitContain scales the image, keeping the aspect ratio, until it fits
+	// entirely in the Canvas, the remaining area is filled with Background
+	FitContain
+	// FitCover scales the image, keeping the aspect ratio, until it covers the
+	// whole Canvas, the parts outside of the Canvas are cropped
+	FitCover
+	// FitStretch scales the image to the size of the Canvas, ignoring the
+	// aspect ratio
+	FitStretch
+	// FitCenter draws the image centered without any scaling
+	FitCenter
+	// FitInteger scales up the image by the biggest integer factor that fits
+	// in the Canvas using nearest-neighbour, ideal for pixel art
+	FitInteger
+	// FitTile repeats the image, without scaling, until the Canvas is covered
+	FitTile
+)
+
+// Resampling is the filter used to scale the images
+type Resampling int
+
+const (
+	// NearestNeighbor is the fastest filter, keeps the edges sharp
+	NearestNeighbor Resampling = iota
+	// Bilinear interpolates the four nearest pixels, smooth when upscaling
+	Bilinear
+	// Box averages all the pixels covered by a LED, good when downscaling
+	Box
+)
+
+// Rotation is a clockwise rotation applied to the images
+type Rotation int
+
+const (
+	Rotate0 Rotation = iota
+	Rotate90
+	Rotate180
+	Rotate270
+)
+
+// Flip mirrors the images, FlipHorizontal and FlipVertical can be combined
+type Flip uint8
+
+const (
+	FlipHorizontal Flip = 1 << iota
+	FlipVertical
+)
+
+// fitCacheSize is the max number of transformed frames kept by a Fit
+const fitCacheSize = 64
+
+// Fit adjusts the images to the size of the Canvas. The images are flipped,
+// rotated and then scaled following Mode. The result of every frame is cached,
+// so playing the same frames again, like in a looping GIF, is almost free.
+type Fit struct {
+	Mode       FitMode
+	Resampling Resampling
+	Rotation   Rotation
+	Flip       Flip
+	// Background is used to fill the area of the Canvas not covered by the
+	// image, black if nil
+	Background color.Color
+
+	m     sync.Mutex
+	cache map[fitKey]*image.RGBA
+}
+
+type fitKey struct {
+	img  image.Image
+	sum  uint32
+	size image.Point
+	fitSettings
+}
+
+// fitSettings are the fields of a Fit used to transform a frame, part of the
+// cache key so changing them doesn't return stale frames
+type fitSettings struct {
+	mode       FitMode
+	resampling Resampling
+	rotation   Rotation
+	flip       Flip
+	background color.RGBA64
+}
+
+// Apply returns the given image adjusted to the given size
+func (f *Fit) Apply(img image.Image, size image.Point) image.Image {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	key, ok := newFitKey(img, size)
+	if !ok {
+		return f.apply(img, size)
+	}
+
+	key.fitSettings = fitSettings{
+		mode:       f.Mode,
+		resampling: f.Resampling,
+		rotation:   f.Rotation,
+		flip:       f.Flip,
+		background: color.RGBA64Model.Convert(f.background()).(color.RGBA64),
+	}
+
+	if cached, ok := f.cache[key]; ok {
+		return cached
+	}
+
+	if f.cache == nil || len(f.cache) >= fitCacheSize {
+		f.cache = make(map[fitKey]*image.RGBA, fitCacheSize)
+	}
+
+	dst := f.apply(img, size)
+	f.cache[key] = dst
+	return dst
+}
+
+// newFitKey returns a cache key for the image, a checksum of the pixels is
+// included since many Animations reuse the same image between frames. Only
+// images with a known pixel buffer can be cached.
+func newFitKey(img image.Image, size image.Point) (fitKey, bool) {
+	var sum uint32
+	switch i := img.(type) {
+	case *image.RGBA:
+		sum = crc32.ChecksumIEEE(i.Pix)
+	case *image.NRGBA:
+		sum = crc32.ChecksumIEEE(i.Pix)
+	case *image.Gray:
+		sum = crc32.ChecksumIEEE(i.Pix)
+	case *image.Paletted:
+		sum = crc32.ChecksumIEEE(i.Pix)
+		for _, c := range i.Palette {
+			r, g, b, a := c.RGBA()
+			sum = crc32.Update(sum, crc32.IEEETable, []byte{
+				byte(r >> 8), byte(g >> 8), byte(b >> 8), byte(a >> 8),
+			})
+		}
+	default:
+		return fitKey{}, false
+	}
+
+	return fitKey{img: img, sum: sum, size: size}, true
+}
+
+func (f *Fit) apply(img image.Image, size image.Point) *image.RGBA {
+	src := orient(img, f.Rotation, f.Flip)
+	dst := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{f.background()}, image.ZP, draw.Src)
+
+	s := src.Bounds().Size()
+	if s.X == 0 || s.Y == 0 || size.X == 0 || size.Y == 0 {
+		return dst
+	}
+
+	switch f.Mode {
+	case FitContain, FitCover:
+		rx, ry := float64(size.X)/float64(s.X), float64(size.Y)/float64(s.Y)
+		r := math.Min(rx, ry)
+		if f.Mode == FitCover {
+			r = math.Max(rx, ry)
+		}
+
+		w, h := round(float64(s.X)*r), round(float64(s.Y)*r)
+		drawCentered(dst, resample(src, w, h, f.Resampling))
+	case FitStretch:
+		draw.Draw(dst, dst.Bounds(), resample(src, size.X, size.Y, f.Resampling), image.ZP, draw.Over)
+	case FitCenter:
+		drawCentered(dst, src)
+	case FitInteger:
+		k := size.X / s.X
+		if ky := size.Y / s.Y; ky < k {
+			k = ky
+		}
+
+		if k < 1 {
+			k = 1
+		}
+
+		drawCentered(dst, resample(src, s.X*k, s.Y*k, NearestNeighbor))
+	case FitTile:
+		for y := 0; y < size.Y; y += s.Y {
+			for x := 0; x < size.X; x += s.X {
+				draw.Draw(dst, src.Bounds().Add(image.Pt(x, y)), src, image.ZP, draw.Over)
+			}
+		}
+	default:
+		draw.Draw(dst, dst.Bounds(), src, image.ZP, draw.Over)
+	}
+
+	return dst
+}
+
+func (f *Fit) background() color.Color {
+	if f.Background == nil {
+		return color.Black
+	}
+
+	return f.Background
+}
+
+func drawCentered(dst, src *image.RGBA) {
+	offset := dst.Bounds().Size().Sub(src.Bounds().Size()).Div(2)
+	draw.Draw(dst, src.Bounds().Add(offset), src, image.ZP, draw.Over)
+}
+
+// orient returns a copy of img, with the origin at 0,0, flipped and rotated
+func orient(img image.Image, r Rotation, f Flip) *image.RGBA {
+	b := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+	if r == Rotate0 && f == 0 {
+		return src
+	}
+
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if r == Rotate90 || r == Rotate270 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			sx, sy := x, y
+			switch r {
+			case Rotate90:
+				sx, sy = y, h-1-x
+			case Rotate180:
+				sx, sy = w-1-x, h-1-y
+			case Rotate270:
+				sx, sy = w-1-y, x
+			}
+
+			if f&FlipHorizontal != 0 {
+				sx = w - 1 - sx
+			}
+
+			if f&FlipVertical != 0 {
+				sy = h - 1 - sy
+			}
+
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, sy):])
+		}
+	}
+
+	return dst
+}
+
+// resample scales src to w x h pixels using the given filter
+func resample(src *image.RGBA, w, h int, r Resampling) *image.RGBA {
+	s := src.Bounds().Size()
+	if s.X == w && s.Y == h {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	if w <= 0 || h <= 0 {
+		return dst
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var px [4]uint8
+			switch r {
+			case Bilinear:
+				px = bilinear(src, (float64(x)+.5)*float64(s.X)/float64(w)-.5, (float64(y)+.5)*float64(s.Y)/float64(h)-.5)
+			case Box:
+				px = box(src, x*s.X/w, y*s.Y/h, (x+1)*s.X/w, (y+1)*s.Y/h)
+			default:
+				i := src.PixOffset((2*x+1)*s.X/(2*w), (2*y+1)*s.Y/(2*h))
+				copy(px[:], src.Pix[i:i+4])
+			}
+
+			copy(dst.Pix[dst.PixOffset(x, y):], px[:])
+		}
+	}
+
+	return dst
+}
+
+func bilinear(src *image.RGBA, fx, fy float64) [4]uint8 {
+	max := src.Bounds().Max
+	x0, y0 := clamp(int(math.Floor(fx)), 0, max.X-1), clamp(int(math.Floor(fy)), 0, max.Y-1)
+	x1, y1 := clamp(x0+1, 0, max.X-1), clamp(y0+1, 0, max.Y-1)
+	dx, dy := clampf(fx-float64(x0), 0, 1), clampf(fy-float64(y0), 0, 1)
+
+	var px [4]uint8
+	for c := 0; c < 4; c++ {
+		p00 := float64(src.Pix[src.PixOffset(x0, y0)+c])
+		p10 := float64(src.Pix[src.PixOffset(x1, y0)+c])
+		p01 := float64(src.Pix[src.PixOffset(x0, y1)+c])
+		p11 := float64(src.Pix[src.PixOffset(x1, y1)+c])
+
+		top := p00 + (p10-p00)*dx
+		bottom := p01 + (p11-p01)*dx
+		px[c] = uint8(round(top + (bottom-top)*dy))
+	}
+
+	return px
+}
+
+func box(src *image.RGBA, x0, y0, x1, y1 int) [4]uint8 {
+	if x1 <= x0 {
+		x1 = x0 + 1
+	}
+
+	if y1 <= y0 {
+		y1 = y0 + 1
+	}
+
+	var sum [4]int
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			i := src.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				sum[c] += int(src.Pix[i+c])
+			}
+		}
+	}
+
+	n := (x1 - x0) * (y1 - y0)
+	var px [4]uint8
+	for c := 0; c < 4; c++ {
+		px[c] = uint8((sum[c] + n/2) / n)
+	}
+
+	return px
+}
+
+func round(f float64) int {
+	return int(math.Floor(f + .5))
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+
+	if v > max {
+		return max
+	}
+
+	return v
+}
+
+func clampf(v, min, max float64) float64 {
+	if v < min {
+		return min
+	}
+
+	if v > max {
+		return max
+	}
+
+	return v
+}
@@ -0,0 +1,123 @@
+package rgbmatrix
+
+import (
+	"image"
+	"image/color"
+
+	. "gopkg.in/check.v1"
+)
+
+type FitSuite struct{}
+
+var _ = Suite(&FitSuite{})
+
+var (
+	red   = color.RGBA{255, 0, 0, 255}
+	blue  = color.RGBA{0, 0, 255, 255}
+	black = color.RGBA{0, 0, 0, 255}
+)
+
+func newTestImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, red)
+		}
+	}
+
+	img.Set(0, 0, blue)
+	return img
+}
+
+func (s *FitSuite) TestContain(c *C) {
+	f := &Fit{Mode: FitContain, Background: black}
+	img := f.Apply(newTestImage(4, 4), image.Pt(8, 4))
+
+	c.Assert(img.Bounds(), Equals, image.Rect(0, 0, 8, 4))
+	c.Assert(img.At(0, 0), Equals, black)
+	c.Assert(img.At(2, 0), Equals, blue)
+	c.Assert(img.At(5, 3), Equals, red)
+	c.Assert(img.At(6, 0), Equals, black)
+}
+
+func (s *FitSuite) TestCover(c *C) {
+	f := &Fit{Mode: FitCover}
+	img := f.Apply(newTestImage(2, 2), image.Pt(8, 4))
+
+	c.Assert(img.At(0, 0), Equals, blue)
+	c.Assert(img.At(3, 0), Equals, blue)
+	c.Assert(img.At(4, 0), Equals, red)
+}
+
+func (s *FitSuite) TestStretch(c *C) {
+	f := &Fit{Mode: FitStretch, Resampling: Box}
+	img := f.Apply(newTestImage(4, 4), image.Pt(2, 1))
+
+	c.Assert(img.Bounds(), Equals, image.Rect(0, 0, 2, 1))
+	c.Assert(img.At(1, 0), Equals, red)
+}
+
+func (s *FitSuite) TestInteger(c *C) {
+	f := &Fit{Mode: FitInteger}
+	img := f.Apply(newTestImage(3, 3), image.Pt(10, 7))
+
+	c.Assert(img.At(1, 0), Equals, black)
+	c.Assert(img.At(2, 0), Equals, blue)
+	c.Assert(img.At(3, 1), Equals, blue)
+	c.Assert(img.At(4, 0), Equals, red)
+	c.Assert(img.At(8, 6), Equals, black)
+}
+
+func (s *FitSuite) TestTile(c *C) {
+	f := &Fit{Mode: FitTile}
+	img := f.Apply(newTestImage(3, 3), image.Pt(7, 4))
+
+	c.Assert(img.At(3, 0), Equals, blue)
+	c.Assert(img.At(6, 3), Equals, blue)
+	c.Assert(img.At(4, 3), Equals, red)
+}
+
+func (s *FitSuite) TestRotation(c *C) {
+	f := &Fit{Rotation: Rotate90}
+	img := f.Apply(newTestImage(4, 2), image.Pt(2, 4))
+	c.Assert(img.At(1, 0), Equals, blue)
+
+	f = &Fit{Rotation: Rotate270}
+	img = f.Apply(newTestImage(4, 2), image.Pt(2, 4))
+	c.Assert(img.At(0, 3), Equals, blue)
+}
+
+func (s *FitSuite) TestFlip(c *C) {
+	f := &Fit{Flip: FlipHorizontal | FlipVertical}
+	img := f.Apply(newTestImage(4, 2), image.Pt(4, 2))
+	c.Assert(img.At(3, 1), Equals, blue)
+}
+
+func (s *FitSuite) TestCache(c *C) {
+	f := &Fit{Mode: FitStretch}
+	src := newTestImage(4, 4)
+
+	first := f.Apply(src, image.Pt(8, 8))
+	c.Assert(f.Apply(src, image.Pt(8, 8)), Equals, first)
+
+	src.Set(0, 0, red)
+	img := f.Apply(src, image.Pt(8, 8))
+	c.Assert(img, Not(Equals), first)
+	c.Assert(img.At(0, 0), Equals, red)
+}
+
+func (s *FitSuite) TestCacheSettings(c *C) {
+	f := &Fit{Mode: FitCenter}
+	src := newTestImage(2, 2)
+
+	first := f.Apply(src, image.Pt(4, 4))
+	c.Assert(first.At(0, 0), Equals, black)
+
+	f.Background = blue
+	img := f.Apply(src, image.Pt(4, 4))
+	c.Assert(img.At(0, 0), Equals, blue)
+
+	f.Flip = FlipHorizontal
+	img = f.Apply(src, image.Pt(4, 4))
+	c.Assert(img.At(2, 1), Equals, blue)
+}
@@ -0,0 +1,144 @@
+// Package input defines the input events produced by the backends with a
+// keyboard or a mouse, as the emulator, allowing to write interactive content
+// as menus or games independent of the backend used.
+package input
+
+import (
+	"fmt"
+	"time"
+)
+
+// Type is the type of an Event
+type Type int
+
+const (
+	// KeyPress is sent when a key is pressed, and repeated while held
+	KeyPress Type = iota + 1
+	// KeyRelease is sent when a key is released
+	KeyRelease
+	// MouseMove is sent when the mouse moves to another LED
+	MouseMove
+	// MousePress is sent when a mouse button is pressed
+	MousePress
+	// MouseRelease is sent when a mouse button is released
+	MouseRelease
+	// MouseWheel is sent for every step of the mouse wheel
+	MouseWheel
+)
+
+func (t Type) String() string {
+	switch t {
+	case KeyPress:
+		return "KeyPress"
+	case KeyRelease:
+		return "KeyRelease"
+	case MouseMove:
+		return "MouseMove"
+	case MousePress:
+		return "MousePress"
+	case MouseRelease:
+		return "MouseRelease"
+	case MouseWheel:
+		return "MouseWheel"
+	default:
+		return fmt.Sprintf("Type(%d)", int(t))
+	}
+}
+
+// Key identifies the keys not producing a character, KeyRune is used for the
+// rest of keys and the character is given in the Rune of the Event
+type Key int
+
+const (
+	KeyUnknown Key = iota
+	KeyRune
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyEnter
+	KeyEscape
+	KeyBackspace
+	KeyDelete
+	KeyTab
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+)
+
+// Button is a mouse button, the direction of the steps of the mouse wheel are
+// given as buttons
+type Button int
+
+const (
+	ButtonNone Button = iota
+	ButtonLeft
+	ButtonMiddle
+	ButtonRight
+	WheelUp
+	WheelDown
+	WheelLeft
+	WheelRight
+)
+
+// Modifiers is a set of the modifier keys held during an Event
+type Modifiers int
+
+const (
+	Shift Modifiers = 1 << iota
+	Control
+	Alt
+	Meta
+)
+
+// Event is an input event, the mouse events have the position of the LED
+// under the mouse
+type Event struct {
+	Type      Type
+	Key       Key
+	Rune      rune
+	Button    Button
+	Modifiers Modifiers
+	// X and Y are the column and row of the LED, in the mouse events
+	X, Y int
+	// Time when the event was received
+	Time time.Time
+}
+
+func (e Event) String() string {
+	switch e.Type {
+	case KeyPress, KeyRelease:
+		if e.Key == KeyRune {
+			return fmt.Sprintf("%s %q", e.Type, e.Rune)
+		}
+
+		return fmt.Sprintf("%s key %d", e.Type, e.Key)
+	default:
+		return fmt.Sprintf("%s button %d at %d,%d", e.Type, e.Button, e.X, e.Y)
+	}
+}
+
+// Handler is a function called with every event
+type Handler func(Event)
+
+// Source is implemented by the backends producing events
+type Source interface {
+	// Events returns the channel receiving the events
+	Events() <-chan Event
+	// Handle sets a function called with every event, from the goroutine
+	// of the backend, so it should not block
+	Handle(Handler)
+}
@@ -0,0 +1,46 @@
+package input
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type InputSuite struct{}
+
+var _ = Suite(&InputSuite{})
+
+func (s *InputSuite) TestQueue(c *C) {
+	q := NewQueue(2)
+	var handled []Event
+	q.Handle(func(e Event) { handled = append(handled, e) })
+
+	events := []Event{
+		{Type: KeyPress, Key: KeyRune, Rune: 'a'},
+		{Type: MouseMove, X: 1, Y: 2},
+		{Type: KeyRelease, Key: KeyRune, Rune: 'a'},
+	}
+
+	for _, e := range events {
+		q.Push(e)
+	}
+
+	c.Assert(handled, DeepEquals, events)
+	c.Assert(<-q.Events(), Equals, events[0])
+	c.Assert(<-q.Events(), Equals, events[1])
+	c.Assert(q.Dropped(), Equals, 1)
+
+	q.Close()
+	q.Push(events[0])
+	_, ok := <-q.Events()
+	c.Assert(ok, Equals, false)
+	c.Assert(handled, HasLen, 4)
+}
+
+func (s *InputSuite) TestString(c *C) {
+	c.Assert(Event{Type: KeyPress, Key: KeyRune, Rune: 'a'}.String(), Equals, `KeyPress 'a'`)
+	c.Assert(Event{Type: KeyRelease, Key: KeyUp}.String(), Equals, "KeyRelease key 2")
+	c.Assert(Event{Type: MousePress, Button: ButtonLeft, X: 3, Y: 4}.String(), Equals, "MousePress button 1 at 3,4")
+}
@@ -0,0 +1,85 @@
+package input
+
+import "sync"
+
+// DefaultQueueSize is the number of events buffered by a Queue when no size
+// is given
+const DefaultQueueSize = 64
+
+// Queue is a Source used by the backends to deliver the events, the events are
+// sent to the handler, if any, and buffered in the channel. The events are
+// dropped when the buffer is full, so a backend never blocks waiting for the
+// events to be read.
+type Queue struct {
+	m       sync.Mutex
+	events  chan Event
+	handler Handler
+	closed  bool
+	dropped int
+}
+
+// NewQueue returns a new Queue buffering size events, DefaultQueueSize if zero
+func NewQueue(size int) *Queue {
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+
+	return &Queue{events: make(chan Event, size)}
+}
+
+// Events returns the channel receiving the events, closed by Close
+func (q *Queue) Events() <-chan Event {
+	return q.events
+}
+
+// Handle sets a function called with every event, nil to remove it
+func (q *Queue) Handle(f Handler) {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	q.handler = f
+}
+
+// Push delivers the event e
+func (q *Queue) Push(e Event) {
+	q.m.Lock()
+	f := q.handler
+	q.m.Unlock()
+
+	if f != nil {
+		f(e)
+	}
+
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	if q.closed {
+		return
+	}
+
+	select {
+	case q.events <- e:
+	default:
+		q.dropped++
+	}
+}
+
+// Dropped returns the number of events dropped because the buffer was full
+func (q *Queue) Dropped() int {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	return q.dropped
+}
+
+// Close closes the channel of events, the events pushed after are only sent to
+// the handler
+func (q *Queue) Close() {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	if !q.closed {
+		q.closed = true
+		close(q.events)
+	}
+}
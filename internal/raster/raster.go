@@ -0,0 +1,104 @@
+// Package raster contains the drawing primitives shared by the packages of
+// the module.
+package raster
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// Line draws the part inside the bounds of dst of the line from p0 to p1,
+// using the Bresenham's algorithm
+func Line(dst draw.Image, p0, p1 image.Point, c color.Color) {
+	p0, p1, ok := clip(dst.Bounds(), p0, p1)
+	if !ok {
+		return
+	}
+
+	dx, dy := abs(p1.X-p0.X), -abs(p1.Y-p0.Y)
+	sx, sy := sign(p1.X-p0.X), sign(p1.Y-p0.Y)
+
+	err := dx + dy
+	for {
+		dst.Set(p0.X, p0.Y, c)
+		if p0 == p1 {
+			return
+		}
+
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			p0.X += sx
+		}
+
+		if e2 <= dx {
+			err += dx
+			p0.Y += sy
+		}
+	}
+}
+
+// clip returns the segment from p0 to p1 clipped to the pixels of r, using
+// the Liang-Barsky algorithm, false if no pixel of the segment is in r
+func clip(r image.Rectangle, p0, p1 image.Point) (image.Point, image.Point, bool) {
+	if p0.In(r) && p1.In(r) {
+		return p0, p1, true
+	}
+
+	x0, y0 := float64(p0.X), float64(p0.Y)
+	dx, dy := float64(p1.X-p0.X), float64(p1.Y-p0.Y)
+
+	t0, t1 := 0.0, 1.0
+	for _, e := range [...]struct{ p, q float64 }{
+		{-dx, x0 - float64(r.Min.X)},
+		{dx, float64(r.Max.X-1) - x0},
+		{-dy, y0 - float64(r.Min.Y)},
+		{dy, float64(r.Max.Y-1) - y0},
+	} {
+		if e.p == 0 {
+			if e.q < 0 {
+				return p0, p1, false
+			}
+
+			continue
+		}
+
+		t := e.q / e.p
+		if e.p < 0 {
+			t0 = math.Max(t0, t)
+		} else {
+			t1 = math.Min(t1, t)
+		}
+	}
+
+	if t0 > t1 {
+		return p0, p1, false
+	}
+
+	at := func(t float64) image.Point {
+		return image.Pt(int(math.Round(x0+t*dx)), int(math.Round(y0+t*dy)))
+	}
+
+	return at(t0), at(t1), true
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,36 @@
+package raster
+
+import (
+	"image"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type RasterSuite struct{}
+
+var _ = Suite(&RasterSuite{})
+
+func (s *RasterSuite) TestClip(c *C) {
+	r := image.Rect(0, 0, 4, 4)
+
+	p0, p1, ok := clip(r, image.Pt(1, 1), image.Pt(2, 3))
+	c.Assert(ok, Equals, true)
+	c.Assert(p0, Equals, image.Pt(1, 1))
+	c.Assert(p1, Equals, image.Pt(2, 3))
+
+	p0, p1, ok = clip(r, image.Pt(-10, 2), image.Pt(10, 2))
+	c.Assert(ok, Equals, true)
+	c.Assert(p0, Equals, image.Pt(0, 2))
+	c.Assert(p1, Equals, image.Pt(3, 2))
+
+	p0, p1, ok = clip(r, image.Pt(-1, -1), image.Pt(5, 5))
+	c.Assert(ok, Equals, true)
+	c.Assert(p0, Equals, image.Pt(0, 0))
+	c.Assert(p1, Equals, image.Pt(3, 3))
+
+	_, _, ok = clip(r, image.Pt(5, 0), image.Pt(9, 3))
+	c.Assert(ok, Equals, false)
+}
@@ -0,0 +1,171 @@
+package rgbmatrix
+
+import (
+	"image"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultLoopFrameRate is the frames per second rendered by a RenderLoop when
+// no FrameRate is given
+const DefaultLoopFrameRate = 30
+
+// RenderLoop renders frames into the Canvas of a ToolKit at a fixed frame
+// rate. The frames are scheduled at fixed times since the start, so the
+// timing doesn't drift, and when a frame is rendered too late, the frames
+// already passed are dropped to catch up with the schedule.
+type RenderLoop struct {
+	// ToolKit used to render the frames, Transform and Fit are applied
+	ToolKit *ToolKit
+	// FrameRate is the frames per second rendered, DefaultLoopFrameRate if
+	// zero
+	FrameRate int
+	// Frame returns the frame scheduled at the given time since the start of
+	// the loop, if io.EOF is returned the loop ends without an error
+	Frame func(t time.Duration) (image.Image, error)
+	// Clock used to schedule the frames, SystemClock if nil
+	Clock Clock
+
+	m sync.Mutex
+	// quit is closed by Stop, nil if not running
+	quit  chan struct{}
+	start time.Time
+	stats RenderStats
+	// jitter is the sum of the deviations from the schedule
+	jitter time.Duration
+}
+
+// RenderStats are the statistics of a RenderLoop
+type RenderStats struct {
+	// Frames is the number of frames rendered
+	Frames int
+	// Dropped is the number of frames skipped to catch up with the schedule
+	Dropped int
+	// Late is the number of frames that finished rendering after the time
+	// scheduled for the next frame
+	Late int
+	// FPS is the achieved frames per second since the start
+	FPS float64
+	// Jitter is the mean deviation of the start of the frames from their
+	// scheduled time
+	Jitter time.Duration
+}
+
+// NewRenderLoop returns a new RenderLoop rendering at the given frame rate the
+// frames returned by f into the Canvas of tk
+func NewRenderLoop(tk *ToolKit, fps int, f func(t time.Duration) (image.Image, error)) *RenderLoop {
+	return &RenderLoop{ToolKit: tk, FrameRate: fps, Frame: f}
+}
+
+// Run renders frames until Frame returns an error or Stop is called, it can be
+// called again once it returns
+func (l *RenderLoop) Run() error {
+	l.m.Lock()
+	quit := make(chan struct{})
+	l.quit = quit
+	clock := ClockOrSystem(l.Clock)
+	l.start = clock.Now()
+	l.stats, l.jitter = RenderStats{}, 0
+	l.m.Unlock()
+
+	defer func() {
+		l.m.Lock()
+		defer l.m.Unlock()
+
+		if l.quit == quit {
+			l.quit = nil
+		}
+	}()
+
+	interval := l.interval()
+	timer := clock.NewTimer(0)
+	defer timer.Stop()
+
+	for frame := 0; ; frame++ {
+		select {
+		case <-timer.C():
+		case <-quit:
+			return nil
+		}
+
+		// the time values keep a monotonic clock reading, so the schedule is
+		// not affected by changes of the wall clock
+		elapsed := clock.Now().Sub(l.start)
+		if late := int(elapsed/interval) - frame; late > 0 {
+			frame += late
+			l.update(func(s *RenderStats) { s.Dropped += late })
+		}
+
+		scheduled := time.Duration(frame) * interval
+		err := l.render(scheduled)
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		next := scheduled + interval
+		now := clock.Now().Sub(l.start)
+		l.update(func(s *RenderStats) {
+			s.Frames++
+			if now > next {
+				s.Late++
+			}
+
+			l.jitter += elapsed - scheduled
+			s.Jitter = l.jitter / time.Duration(s.Frames)
+			if now > 0 {
+				s.FPS = float64(s.Frames) / now.Seconds()
+			}
+		})
+
+		timer.Reset(next - now)
+	}
+}
+
+func (l *RenderLoop) render(t time.Duration) error {
+	i, err := l.Frame(t)
+	if err != nil {
+		return err
+	}
+
+	return l.ToolKit.render(l.ToolKit.prepare(i))
+}
+
+// Stop stops the running loop, Run returns after the frame being rendered.
+// Does nothing if the loop is not running.
+func (l *RenderLoop) Stop() {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if l.quit != nil {
+		close(l.quit)
+		l.quit = nil
+	}
+}
+
+// Stats returns the statistics of the loop
+func (l *RenderLoop) Stats() RenderStats {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	return l.stats
+}
+
+func (l *RenderLoop) update(f func(s *RenderStats)) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	f(&l.stats)
+}
+
+func (l *RenderLoop) interval() time.Duration {
+	if l.FrameRate <= 0 {
+		return time.Second / DefaultLoopFrameRate
+	}
+
+	return time.Second / time.Duration(l.FrameRate)
+}
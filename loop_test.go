@@ -0,0 +1,107 @@
+package rgbmatrix
+
+import (
+	"image"
+	"io"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type RenderLoopSuite struct{}
+
+var _ = Suite(&RenderLoopSuite{})
+
+func (s *RenderLoopSuite) TestRun(c *C) {
+	clock := NewFakeClock(epoch)
+	tk, m := newRecorderToolKit()
+
+	var times []time.Duration
+	l := NewRenderLoop(tk, 100, func(t time.Duration) (image.Image, error) {
+		if len(times) == 5 {
+			return nil, io.EOF
+		}
+
+		times = append(times, t)
+		return newUniformRGBA(10, 20, red), nil
+	})
+	l.Clock = clock
+
+	done := make(chan error)
+	go func() { done <- l.Run() }()
+
+	for i := 0; i < 5; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(10 * time.Millisecond)
+	}
+
+	c.Assert(<-done, IsNil)
+	c.Assert(m.frames, HasLen, 5)
+	c.Assert(times, DeepEquals, []time.Duration{
+		0, 10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond, 40 * time.Millisecond,
+	})
+
+	stats := l.Stats()
+	c.Assert(stats.Frames, Equals, 5)
+	c.Assert(stats.Late, Equals, 0)
+	c.Assert(stats.FPS, Equals, 125.0)
+}
+
+func (s *RenderLoopSuite) TestDrop(c *C) {
+	clock := NewFakeClock(epoch)
+	tk, _ := newRecorderToolKit()
+
+	var times []time.Duration
+	l := NewRenderLoop(tk, 100, func(t time.Duration) (image.Image, error) {
+		times = append(times, t)
+		switch len(times) {
+		case 1:
+			clock.Advance(35 * time.Millisecond)
+		case 3:
+			return nil, io.EOF
+		}
+
+		return newUniformRGBA(10, 20, red), nil
+	})
+	l.Clock = clock
+
+	done := make(chan error)
+	go func() { done <- l.Run() }()
+
+	clock.BlockUntil(1)
+	clock.Advance(5 * time.Millisecond)
+
+	c.Assert(<-done, IsNil)
+	c.Assert(times, DeepEquals, []time.Duration{0, 30 * time.Millisecond, 40 * time.Millisecond})
+
+	stats := l.Stats()
+	c.Assert(stats.Late, Equals, 1)
+	c.Assert(stats.Dropped, Equals, 2)
+}
+
+func (s *RenderLoopSuite) TestStop(c *C) {
+	clock := NewFakeClock(epoch)
+	tk, _ := newRecorderToolKit()
+	l := NewRenderLoop(tk, 100, func(t time.Duration) (image.Image, error) {
+		return newUniformRGBA(10, 20, red), nil
+	})
+	l.Clock = clock
+
+	go func() {
+		clock.BlockUntil(1)
+		l.Stop()
+	}()
+
+	c.Assert(l.Run(), IsNil)
+	c.Assert(l.Stats().Frames, Equals, 1)
+	// the frame was rendered without any time elapsed
+	c.Assert(l.Stats().FPS, Equals, 0.0)
+
+	go func() {
+		clock.BlockUntil(1)
+		l.Stop()
+	}()
+
+	c.Assert(l.Run(), IsNil)
+	c.Assert(l.Stats().Frames, Equals, 1)
+}
@@ -0,0 +1,159 @@
+//go:build linux && (arm || arm64) && cgo
+
+package rgbmatrix
+
+/*
+#cgo CFLAGS: -std=c99 -I${SRCDIR}/vendor/rpi-rgb-led-matrix/include -DSHOW_REFRESH_RATE
+#cgo LDFLAGS: -lrgbmatrix -L${SRCDIR}/vendor/rpi-rgb-led-matrix/lib -lstdc++ -lm
+#include <led-matrix-c.h>
+
+void led_matrix_swap(struct RGBLedMatrix *matrix, struct LedCanvas *offscreen_canvas,
+                     int width, int height, const uint32_t pixels[]) {
+
+
+  int i, x, y;
+  uint32_t color;
+  for (x = 0; x < width; ++x) {
+    for (y = 0; y < height; ++y) {
+      i = x + (y * width);
+      color = pixels[i];
+
+      led_canvas_set_pixel(offscreen_canvas, x, y,
+        (color >> 16) & 255, (color >> 8) & 255, color & 255);
+    }
+  }
+
+  offscreen_canvas = led_matrix_swap_on_vsync(matrix, offscreen_canvas);
+}
+
+void set_show_refresh_rate(struct RGBLedMatrixOptions *o, int show_refresh_rate) {
+  o->show_refresh_rate = show_refresh_rate != 0 ? 1 : 0;
+}
+
+void set_disable_hardware_pulsing(struct RGBLedMatrixOptions *o, int disable_hardware_pulsing) {
+  o->disable_hardware_pulsing = disable_hardware_pulsing != 0 ? 1 : 0;
+}
+
+void set_inverse_colors(struct RGBLedMatrixOptions *o, int inverse_colors) {
+  o->inverse_colors = inverse_colors != 0 ? 1 : 0;
+}
+*/
+import "C"
+import (
+	"fmt"
+	"image/color"
+	"unsafe"
+)
+
+func (c *HardwareConfig) toC() *C.struct_RGBLedMatrixOptions {
+	o := &C.struct_RGBLedMatrixOptions{}
+	o.rows = C.int(c.Rows)
+	o.cols = C.int(c.Cols)
+	o.chain_length = C.int(c.ChainLength)
+	o.parallel = C.int(c.Parallel)
+	o.pwm_bits = C.int(c.PWMBits)
+	o.pwm_lsb_nanoseconds = C.int(c.PWMLSBNanoseconds)
+	o.brightness = C.int(c.Brightness)
+	o.scan_mode = C.int(c.ScanMode)
+	o.hardware_mapping = C.CString(c.HardwareMapping)
+
+	if c.ShowRefreshRate == true {
+		C.set_show_refresh_rate(o, C.int(1))
+	} else {
+		C.set_show_refresh_rate(o, C.int(0))
+	}
+
+	if c.DisableHardwarePulsing == true {
+		C.set_disable_hardware_pulsing(o, C.int(1))
+	} else {
+		C.set_disable_hardware_pulsing(o, C.int(0))
+	}
+
+	if c.InverseColors == true {
+		C.set_inverse_colors(o, C.int(1))
+	} else {
+		C.set_inverse_colors(o, C.int(0))
+	}
+
+	return o
+}
+
+// RGBLedMatrix matrix representation for ws281x
+type RGBLedMatrix struct {
+	Config *HardwareConfig
+
+	height int
+	width  int
+	matrix *C.struct_RGBLedMatrix
+	buffer *C.struct_LedCanvas
+	leds   []C.uint32_t
+}
+
+func newRGBLedMatrix(config *HardwareConfig) (Matrix, error) {
+	w, h := config.geometry()
+	m := C.led_matrix_create_from_options(config.toC(), nil, nil)
+	if m == nil {
+		return nil, fmt.Errorf("unable to allocate memory")
+	}
+
+	b := C.led_matrix_create_offscreen_canvas(m)
+	return &RGBLedMatrix{
+		Config: config,
+		width:  w, height: h,
+		matrix: m,
+		buffer: b,
+		leds:   make([]C.uint32_t, w*h),
+	}, nil
+}
+
+// Initialize initialize library, must be called once before other functions are
+// called.
+func (c *RGBLedMatrix) Initialize() error {
+	return nil
+}
+
+// Geometry returns the width and the height of the matrix
+func (c *RGBLedMatrix) Geometry() (width, height int) {
+	return c.width, c.height
+}
+
+// Apply set all the pixels to the values contained in leds
+func (c *RGBLedMatrix) Apply(leds []color.Color) error {
+	for position, l := range leds {
+		c.Set(position, l)
+	}
+
+	return c.Render()
+}
+
+// Render update the display with the data from the LED buffer
+func (c *RGBLedMatrix) Render() error {
+	w, h := c.Config.geometry()
+
+	C.led_matrix_swap(
+		c.matrix,
+		c.buffer,
+		C.int(w), C.int(h),
+		(*C.uint32_t)(unsafe.Pointer(&c.leds[0])),
+	)
+
+	c.leds = make([]C.uint32_t, w*h)
+	return nil
+}
+
+// At return an Color which allows access to the LED display data as
+// if it were a sequence of 24-bit RGB values.
+func (c *RGBLedMatrix) At(position int) color.Color {
+	return uint32ToColor(uint32(c.leds[position]))
+}
+
+// Set set LED at position x,y to the provided 24-bit color value.
+func (c *RGBLedMatrix) Set(position int, color color.Color) {
+	c.leds[position] = C.uint32_t(colorToUint32(color))
+}
+
+// Close finalizes the ws281x interface
+func (c *RGBLedMatrix) Close() error {
+	C.led_matrix_delete(c.matrix)
+	return nil
+}
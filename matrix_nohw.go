@@ -0,0 +1,48 @@
+//go:build !(linux && (arm || arm64) && cgo)
+
+package rgbmatrix
+
+import "image/color"
+
+// RGBLedMatrix matrix representation for ws281x, not available in this build,
+// see ErrHardwareUnavailable
+type RGBLedMatrix struct {
+	Config *HardwareConfig
+}
+
+func newRGBLedMatrix(config *HardwareConfig) (Matrix, error) {
+	return nil, ErrHardwareUnavailable
+}
+
+// Initialize returns ErrHardwareUnavailable
+func (c *RGBLedMatrix) Initialize() error {
+	return ErrHardwareUnavailable
+}
+
+// Geometry returns the width and the height of the matrix
+func (c *RGBLedMatrix) Geometry() (width, height int) {
+	return c.Config.geometry()
+}
+
+// Apply returns ErrHardwareUnavailable
+func (c *RGBLedMatrix) Apply(leds []color.Color) error {
+	return ErrHardwareUnavailable
+}
+
+// Render returns ErrHardwareUnavailable
+func (c *RGBLedMatrix) Render() error {
+	return ErrHardwareUnavailable
+}
+
+// At returns black
+func (c *RGBLedMatrix) At(position int) color.Color {
+	return color.Black
+}
+
+// Set does nothing
+func (c *RGBLedMatrix) Set(position int, color color.Color) {}
+
+// Close returns ErrHardwareUnavailable
+func (c *RGBLedMatrix) Close() error {
+	return ErrHardwareUnavailable
+}
@@ -0,0 +1,20 @@
+//go:build !(linux && (arm || arm64) && cgo)
+
+package rgbmatrix
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type NoHardwareSuite struct{}
+
+var _ = Suite(&NoHardwareSuite{})
+
+func (s *NoHardwareSuite) TestNewRGBLedMatrix(c *C) {
+	config := DefaultConfig
+	_, err := NewRGBLedMatrix(&config)
+	c.Assert(err, Equals, ErrHardwareUnavailable)
+
+	_, err = NewMatrix("hw://", nil)
+	c.Assert(err, Equals, ErrHardwareUnavailable)
+}
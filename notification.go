@@ -0,0 +1,288 @@
+package rgbmatrix
+
+import (
+	"image"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultNotificationDuration is the duration of the notifications without
+// Duration showing an Image
+const DefaultNotificationDuration = 5 * time.Second
+
+// Notification is shown by a ToolKit interrupting the content being played,
+// once the notification ends the frame of the content is shown again. The
+// frame isn't paused meanwhile, so it is shown only during the time it has
+// left, if any. A Notification can be passed again to Notify once it has been
+// shown, it is shown again during its whole duration.
+type Notification struct {
+	// Image shown by the notification, ignored if Animation is not nil
+	Image image.Image
+	// Animation played by the notification, the Animation is not reset when
+	// the notification is preempted
+	Animation Animation
+	// Priority of the notification, the queued notifications with higher
+	// priority are shown first
+	Priority int
+	// Duration of the notification, if zero an Animation is played until it
+	// ends and an Image is shown during DefaultNotificationDuration
+	Duration time.Duration
+	// Enter if not nil is the transition from the content to the notification
+	Enter *Transition
+	// Exit if not nil is the transition from the notification to the content
+	Exit *Transition
+	// Preempt allows the notification to interrupt a notification with lower
+	// priority being shown, the interrupted notification is queued again and
+	// shown later during its remaining duration
+	Preempt bool
+
+	// shown is the time the notification was shown before being preempted
+	shown time.Duration
+	seq   int
+}
+
+// animation returns the Animation played, the Image is shown until the
+// deadline of the notification
+func (n *Notification) animation() Animation {
+	if n.Animation == nil {
+		return &stillAnimation{img: n.Image}
+	}
+
+	return n.Animation
+}
+
+// stillAnimation shows an image until it is interrupted
+type stillAnimation struct {
+	img    image.Image
+	played bool
+}
+
+func (a *stillAnimation) Next() (image.Image, <-chan time.Time, error) {
+	if a.played {
+		return nil, nil, io.EOF
+	}
+
+	a.played = true
+	return a.img, nil, nil
+}
+
+func (n *Notification) duration() time.Duration {
+	if n.Duration == 0 && n.Animation == nil {
+		return DefaultNotificationDuration
+	}
+
+	return n.Duration
+}
+
+// preempts returns true if n can interrupt other
+func (n *Notification) preempts(other *Notification) bool {
+	return n.Preempt && n.Priority > other.Priority
+}
+
+// notificationQueue is a priority queue of notifications
+type notificationQueue struct {
+	m     sync.Mutex
+	queue []*Notification
+	seq   int
+	wake  chan struct{}
+}
+
+// push queues n to be shown during its whole duration, and wakes up the
+// ToolKit
+func (q *notificationQueue) push(n *Notification) {
+	q.m.Lock()
+	n.shown = 0
+	q.m.Unlock()
+
+	q.requeue(n)
+
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	select {
+	case q.wakeChan() <- struct{}{}:
+	default:
+	}
+}
+
+// requeue queues n without waking up the ToolKit, used to queue again a
+// notification preempted while the ToolKit is already awake, keeping the time
+// it was already shown
+func (q *notificationQueue) requeue(n *Notification) {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	n.seq = q.seq
+	q.seq++
+
+	q.queue = append(q.queue, n)
+	sort.Slice(q.queue, func(i, j int) bool {
+		if q.queue[i].Priority != q.queue[j].Priority {
+			return q.queue[i].Priority > q.queue[j].Priority
+		}
+
+		return q.queue[i].seq < q.queue[j].seq
+	})
+}
+
+// pop returns the notification with the highest priority, if any
+func (q *notificationQueue) pop() *Notification {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	if len(q.queue) == 0 {
+		return nil
+	}
+
+	n := q.queue[0]
+	q.queue = q.queue[1:]
+	return n
+}
+
+// peek returns the notification with the highest priority, if any
+func (q *notificationQueue) peek() *Notification {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	if len(q.queue) == 0 {
+		return nil
+	}
+
+	return q.queue[0]
+}
+
+// signal returns a chan receiving a value every time a notification is queued
+func (q *notificationQueue) signal() <-chan struct{} {
+	q.m.Lock()
+	defer q.m.Unlock()
+
+	return q.wakeChan()
+}
+
+func (q *notificationQueue) wakeChan() chan struct{} {
+	if q.wake == nil {
+		q.wake = make(chan struct{}, 1)
+	}
+
+	return q.wake
+}
+
+// Notify queues the given notification, the notification is shown as soon as
+// possible, interrupting the content being played by PlayImage, PlayImageUntil,
+// PlayAnimation, PlayImages or PlayGIF
+func (tk *ToolKit) Notify(n *Notification) {
+	tk.notifications.push(n)
+}
+
+// wait waits until notify receives a value, showing the notifications queued
+// meanwhile, current is the image being shown
+func (tk *ToolKit) wait(current image.Image, notify <-chan time.Time) error {
+	for {
+		select {
+		case <-notify:
+			return nil
+		case <-tk.notifications.signal():
+			if err := tk.showNotifications(current); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// showNotifications shows all the queued notifications, and then draws again
+// current, the image being shown before
+func (tk *ToolKit) showNotifications(current image.Image) error {
+	last := current
+	for {
+		n := tk.notifications.pop()
+		if n == nil {
+			break
+		}
+
+		var err error
+		if last, err = tk.showNotification(n, last); err != nil {
+			return err
+		}
+
+		if tk.notifications.peek() != nil {
+			continue
+		}
+
+		if n.Exit != nil {
+			return tk.playTransition(last, current, n.Exit)
+		}
+	}
+
+	return tk.render(current)
+}
+
+// showNotification plays n, using its Enter transition from the image from,
+// it returns the last frame shown. The duration of n is counted since its
+// first frame is shown, after the Enter transition.
+func (tk *ToolKit) showNotification(n *Notification, from image.Image) (image.Image, error) {
+	var deadline <-chan time.Time
+	var start time.Time
+	defer func() {
+		if !start.IsZero() {
+			n.shown += tk.clock().Now().Sub(start)
+		}
+	}()
+
+	a := n.animation()
+	SetClock(a, tk.Clock)
+	for first := true; ; first = false {
+		i, next, err := a.Next()
+		if err == io.EOF {
+			return from, nil
+		}
+
+		if err != nil {
+			return from, err
+		}
+
+		i = tk.prepare(i)
+		if first && n.Enter != nil {
+			err = tk.playTransition(from, i, n.Enter)
+		} else {
+			err = tk.render(i)
+		}
+
+		if err != nil {
+			return i, err
+		}
+
+		if first {
+			start = tk.clock().Now()
+			if d := n.duration(); d > 0 {
+				timer := tk.clock().NewTimer(d - n.shown)
+				defer timer.Stop()
+				deadline = timer.C()
+			}
+		}
+
+		from = i
+		if tk.waitNotification(n, next, deadline) {
+			return i, nil
+		}
+	}
+}
+
+// waitNotification waits until next receives a value, it returns true if the
+// notification n has to end, because its deadline or a preemption
+func (tk *ToolKit) waitNotification(n *Notification, next, deadline <-chan time.Time) bool {
+	for {
+		select {
+		case <-next:
+			return false
+		case <-deadline:
+			return true
+		case <-tk.notifications.signal():
+			if other := tk.notifications.peek(); other != nil && other.preempts(n) {
+				tk.notifications.requeue(n)
+				return true
+			}
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package rgbmatrix
+
+import (
+	"image/color"
+	"sync"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type NotificationSuite struct{}
+
+var _ = Suite(&NotificationSuite{})
+
+var green = color.RGBA{0, 255, 0, 255}
+
+// recorderMatrix records the color of the first LED on every Render
+type recorderMatrix struct {
+	*MatrixMock
+	frames []color.Color
+
+	m        sync.Mutex
+	rendered *sync.Cond
+}
+
+func (m *recorderMatrix) Render() error {
+	m.m.Lock()
+	m.frames = append(m.frames, color.RGBAModel.Convert(m.colors[0]))
+	m.m.Unlock()
+
+	m.rendered.Broadcast()
+	return m.MatrixMock.Render()
+}
+
+// waitFrames blocks until n frames are rendered
+func (m *recorderMatrix) waitFrames(n int) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	for len(m.frames) < n {
+		m.rendered.Wait()
+	}
+}
+
+func newRecorderToolKit() (*ToolKit, *recorderMatrix) {
+	m := &recorderMatrix{MatrixMock: NewMatrixMock()}
+	m.rendered = sync.NewCond(&m.m)
+	return &ToolKit{Canvas: &Canvas{w: 10, h: 20, m: m}}, m
+}
+
+func (s *NotificationSuite) TestNotify(c *C) {
+	clock := NewFakeClock(epoch)
+	tk, m := newRecorderToolKit()
+	tk.Clock = clock
+	tk.Notify(&Notification{Image: newUniformRGBA(10, 20, blue), Duration: time.Second})
+
+	until := clock.After(time.Minute)
+	done := make(chan error)
+	go func() { done <- tk.PlayImageUntil(newUniformRGBA(10, 20, red), until) }()
+
+	clock.BlockUntil(2)
+	clock.Advance(time.Second)
+	clock.Advance(time.Minute)
+
+	c.Assert(<-done, IsNil)
+	c.Assert(m.frames, DeepEquals, []color.Color{red, blue, red})
+}
+
+func (s *NotificationSuite) TestNotifyTwice(c *C) {
+	clock := NewFakeClock(epoch)
+	tk, m := newRecorderToolKit()
+	tk.Clock = clock
+	n := &Notification{Image: newUniformRGBA(10, 20, blue), Duration: time.Second}
+	tk.Notify(n)
+
+	until := clock.After(time.Minute)
+	done := make(chan error)
+	go func() { done <- tk.PlayImageUntil(newUniformRGBA(10, 20, red), until) }()
+
+	clock.BlockUntil(2)
+	clock.Advance(time.Second)
+	m.waitFrames(3)
+
+	// shown again during its whole duration
+	tk.Notify(n)
+	m.waitFrames(4)
+	clock.BlockUntil(2)
+	clock.Advance(time.Second - time.Nanosecond)
+	c.Assert(clock.Waiters(), Equals, 2)
+	clock.Advance(time.Nanosecond)
+	clock.Advance(time.Minute)
+
+	c.Assert(<-done, IsNil)
+	c.Assert(m.frames, DeepEquals, []color.Color{red, blue, red, blue, red})
+}
+
+func (s *NotificationSuite) TestPriority(c *C) {
+	clock := NewFakeClock(epoch)
+	tk, m := newRecorderToolKit()
+	tk.Clock = clock
+	tk.Notify(&Notification{Image: newUniformRGBA(10, 20, green), Duration: time.Second})
+	tk.Notify(&Notification{Image: newUniformRGBA(10, 20, blue), Duration: time.Second, Priority: 1})
+
+	until := clock.After(time.Minute)
+	done := make(chan error)
+	go func() { done <- tk.PlayImageUntil(newUniformRGBA(10, 20, red), until) }()
+
+	clock.BlockUntil(2)
+	clock.Advance(time.Second)
+	clock.BlockUntil(2)
+	clock.Advance(time.Second)
+	clock.Advance(time.Minute)
+
+	c.Assert(<-done, IsNil)
+	c.Assert(m.frames, DeepEquals, []color.Color{red, blue, green, red})
+}
+
+func (s *NotificationSuite) TestPreempt(c *C) {
+	clock := NewFakeClock(epoch)
+	tk, m := newRecorderToolKit()
+	tk.Clock = clock
+	tk.Notify(&Notification{Image: newUniformRGBA(10, 20, green), Duration: 10 * time.Second})
+
+	until := clock.After(time.Second)
+	done := make(chan error)
+	go func() { done <- tk.PlayImageUntil(newUniformRGBA(10, 20, red), until) }()
+
+	m.waitFrames(2)
+	clock.BlockUntil(2)
+	clock.Advance(2 * time.Second)
+	tk.Notify(&Notification{
+		Image:    newUniformRGBA(10, 20, blue),
+		Duration: time.Second,
+		Priority: 1,
+		Preempt:  true,
+	})
+
+	m.waitFrames(3)
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+
+	// the green notification is shown again during its remaining 8 seconds
+	m.waitFrames(4)
+	clock.BlockUntil(1)
+	clock.Advance(8 * time.Second)
+
+	c.Assert(<-done, IsNil)
+	c.Assert(m.frames, DeepEquals, []color.Color{red, green, blue, green, red})
+}
+
+func (s *NotificationSuite) TestEnterTransition(c *C) {
+	clock := NewFakeClock(epoch)
+	tk, m := newRecorderToolKit()
+	tk.Clock = clock
+	tk.Notify(&Notification{
+		Image:    newUniformRGBA(10, 20, blue),
+		Duration: time.Second,
+		Enter:    &Transition{Effect: Crossfade, Duration: 2 * time.Second, FrameRate: 1},
+	})
+
+	until := clock.After(time.Minute)
+	done := make(chan error)
+	go func() { done <- tk.PlayImageUntil(newUniformRGBA(10, 20, red), until) }()
+
+	for i := 0; i < 2; i++ {
+		clock.BlockUntil(2)
+		clock.Advance(time.Second)
+	}
+
+	// the duration starts once the transition ends
+	m.waitFrames(4)
+	clock.BlockUntil(2)
+	clock.Advance(time.Second - time.Nanosecond)
+	m.m.Lock()
+	c.Assert(m.frames, HasLen, 4)
+	m.m.Unlock()
+
+	clock.Advance(time.Nanosecond)
+	m.waitFrames(5)
+	clock.Advance(time.Minute)
+
+	c.Assert(<-done, IsNil)
+	c.Assert(m.frames[3:], DeepEquals, []color.Color{blue, red})
+}
@@ -0,0 +1,363 @@
+// Package playlist plays a list of items, images, GIFs, texts or Animations,
+// on a ToolKit, choosing every time the next item between the items allowed
+// by their time rules.
+package playlist
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+)
+
+// CheckInterval is how often the rule of the item being played is checked,
+// and how often the items are checked when none is active
+var CheckInterval = time.Second
+
+// Item is an element of a playlist
+type Item struct {
+	// Name of the item, informative
+	Name string
+	// Source returns a new Animation every time the item is played, the given
+	// bounds are the bounds of the Canvas
+	Source func(bounds image.Rectangle) (rgbmatrix.Animation, error)
+	// Duration is the max time the item is played, if zero the item is played
+	// until the Animation ends
+	Duration time.Duration
+	// Weight is the relative probability of the item of being chosen between
+	// the active items, 1 if zero
+	Weight int
+	// Rule defines when the item can be played, always if nil. If the Rule
+	// stops matching while the item is played, the item is stopped
+	Rule Rule
+}
+
+// Image returns an Item showing the given image during the given duration
+func Image(img image.Image, d time.Duration) *Item {
+	return &Item{
+		Source: func(image.Rectangle) (rgbmatrix.Animation, error) {
+			return rgbmatrix.NewImageAnimation(img, d), nil
+		},
+		Duration: d,
+	}
+}
+
+// GIF returns an Item playing the given GIF, until it ends
+func GIF(g *gif.GIF) *Item {
+	return &Item{
+		Source: func(image.Rectangle) (rgbmatrix.Animation, error) {
+			return rgbmatrix.NewGIFAnimation(g), nil
+		},
+	}
+}
+
+// Text returns an Item showing the given text during the given duration, the
+// text is scrolled if it doesn't fit in the Canvas
+func Text(text string, c color.Color, d time.Duration) *Item {
+	return &Item{
+		Source: func(b image.Rectangle) (rgbmatrix.Animation, error) {
+			a := rgbmatrix.NewTextAnimation(text, b.Size())
+			a.Color = c
+			return a, nil
+		},
+		Duration: d,
+	}
+}
+
+// Animation returns an Item playing the Animations returned by f
+func Animation(f func() rgbmatrix.Animation) *Item {
+	return &Item{
+		Source: func(image.Rectangle) (rgbmatrix.Animation, error) {
+			return f(), nil
+		},
+	}
+}
+
+func (i *Item) active(t time.Time) bool {
+	return i.Rule == nil || i.Rule.Match(t)
+}
+
+func (i *Item) weight() int {
+	if i.Weight <= 0 {
+		return 1
+	}
+
+	return i.Weight
+}
+
+// Scheduler plays a playlist of items on a ToolKit. Every time an item ends,
+// the next item is chosen randomly, using the weights, between the items
+// whose Rule matches, or in order if Sequential is true.
+type Scheduler struct {
+	ToolKit *rgbmatrix.ToolKit
+	Items   []*Item
+	// Sequential plays the active items in order, ignoring the weights
+	Sequential bool
+	// NoRepeat avoids playing the same item twice in a row when other items
+	// are active, ignored if Sequential
+	NoRepeat bool
+	// Rand is the source used to choose the items, only used holding the lock
+	// of the Scheduler, since a rand.Rand is not safe for concurrent use
+	Rand *rand.Rand
+	// Clock used to check the rules and the durations of the items,
+	// SystemClock if nil. It is set to the Animations of the items.
+	Clock rgbmatrix.Clock
+
+	m       sync.Mutex
+	last    int
+	err     error
+	current *Item
+}
+
+// NewScheduler returns a new Scheduler playing the given items on tk
+func NewScheduler(tk *rgbmatrix.ToolKit, items ...*Item) *Scheduler {
+	return &Scheduler{
+		ToolKit: tk,
+		Items:   items,
+		Rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		last:    -1,
+	}
+}
+
+// Next returns the index of the item to be played at t, or -1 if no item is
+// active
+func (s *Scheduler) Next(t time.Time) int {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var active []int
+	var total int
+	for i, item := range s.Items {
+		if item.active(t) {
+			active = append(active, i)
+			total += item.weight()
+		}
+	}
+
+	if len(active) == 0 {
+		return -1
+	}
+
+	if s.Sequential {
+		for _, i := range active {
+			if i > s.last {
+				return i
+			}
+		}
+
+		return active[0]
+	}
+
+	skip := -1
+	if s.NoRepeat && len(active) > 1 && s.last != -1 && s.Items[s.last].active(t) {
+		skip = s.last
+		total -= s.Items[skip].weight()
+	}
+
+	n := s.Rand.Intn(total)
+	for _, i := range active {
+		if i == skip {
+			continue
+		}
+
+		if n -= s.Items[i].weight(); n < 0 {
+			return i
+		}
+	}
+
+	return active[0]
+}
+
+// Current returns the item being played, if any
+func (s *Scheduler) Current() *Item {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.current
+}
+
+// Err returns the last error returned by an item
+func (s *Scheduler) Err() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.err
+}
+
+// Play plays the items until a true is sent to the returned chan. The items
+// returning an error are skipped, the error is returned by Err.
+func (s *Scheduler) Play() chan bool {
+	quit := make(chan bool, 0)
+	done := make(chan struct{})
+	go func() {
+		<-quit
+		close(done)
+	}()
+
+	clock := s.clock()
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			i := s.Next(clock.Now())
+			if i == -1 {
+				s.ToolKit.Canvas.Clear()
+				select {
+				case <-done:
+					return
+				case <-clock.After(CheckInterval):
+				}
+
+				continue
+			}
+
+			s.m.Lock()
+			s.last = i
+			s.m.Unlock()
+
+			if err := s.play(s.Items[i], done); err != nil {
+				s.m.Lock()
+				s.err = err
+				s.m.Unlock()
+			}
+		}
+	}()
+
+	return quit
+}
+
+func (s *Scheduler) play(item *Item, done chan struct{}) error {
+	a, err := item.Source(s.ToolKit.Canvas.Bounds())
+	if err != nil {
+		return err
+	}
+
+	s.m.Lock()
+	s.current = item
+	s.m.Unlock()
+
+	defer func() {
+		s.m.Lock()
+		s.current = nil
+		s.m.Unlock()
+	}()
+
+	clock := s.clock()
+	rgbmatrix.SetClock(a, clock)
+	return s.ToolKit.PlayAnimation(&boundedAnimation{
+		Animation: a,
+		item:      item,
+		clock:     clock,
+		start:     clock.Now(),
+		done:      done,
+	})
+}
+
+func (s *Scheduler) clock() rgbmatrix.Clock {
+	return rgbmatrix.ClockOrSystem(s.Clock)
+}
+
+// boundedAnimation ends the Animation of an item when its duration is
+// reached, its rule stops matching or the Scheduler is stopped
+type boundedAnimation struct {
+	rgbmatrix.Animation
+	item  *Item
+	clock rgbmatrix.Clock
+	start time.Time
+	done  chan struct{}
+
+	m       sync.Mutex
+	stopped bool
+}
+
+func (a *boundedAnimation) Next() (image.Image, <-chan time.Time, error) {
+	if a.isStopped() || a.ended(a.clock.Now()) {
+		return nil, nil, io.EOF
+	}
+
+	img, next, err := a.Animation.Next()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wait := make(chan time.Time, 1)
+	go a.wait(next, wait)
+
+	return img, wait, nil
+}
+
+// SetClock sets the Clock of the boundedAnimation and the Animation of the item
+func (a *boundedAnimation) SetClock(c rgbmatrix.Clock) {
+	a.clock = c
+	rgbmatrix.SetClock(a.Animation, c)
+}
+
+// stop ends the animation, called by wait once the item has to be stopped
+func (a *boundedAnimation) stop() {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	a.stopped = true
+}
+
+func (a *boundedAnimation) isStopped() bool {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	return a.stopped
+}
+
+func (a *boundedAnimation) ended(t time.Time) bool {
+	if a.item.Duration > 0 && t.Sub(a.start) >= a.item.Duration {
+		return true
+	}
+
+	return !a.item.active(t)
+}
+
+// wait forwards next to wait, unless the animation is ended before
+func (a *boundedAnimation) wait(next <-chan time.Time, wait chan<- time.Time) {
+	clock := a.clock
+	check := clock.NewTimer(CheckInterval)
+	defer check.Stop()
+
+	var deadline <-chan time.Time
+	if a.item.Duration > 0 {
+		timer := clock.NewTimer(a.item.Duration - clock.Now().Sub(a.start))
+		defer timer.Stop()
+		deadline = timer.C()
+	}
+
+	for {
+		select {
+		case t := <-next:
+			wait <- t
+			return
+		case t := <-deadline:
+			a.stop()
+			wait <- t
+			return
+		case t := <-check.C():
+			if a.ended(t) {
+				a.stop()
+				wait <- t
+				return
+			}
+
+			check.Reset(CheckInterval)
+		case <-a.done:
+			a.stop()
+			wait <- clock.Now()
+			return
+		}
+	}
+}
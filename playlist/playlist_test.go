@@ -0,0 +1,104 @@
+package playlist
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+	. "gopkg.in/check.v1"
+)
+
+type PlaylistSuite struct{}
+
+var _ = Suite(&PlaylistSuite{})
+
+// matrixMock records the color of the first LED on every Render
+type matrixMock struct {
+	m      sync.Mutex
+	leds   [16]color.Color
+	frames []color.Color
+}
+
+func (m *matrixMock) Geometry() (int, int)           { return 4, 4 }
+func (m *matrixMock) Set(i int, c color.Color)       { m.leds[i] = c }
+func (m *matrixMock) Apply(leds []color.Color) error { copy(m.leds[:], leds); return nil }
+func (m *matrixMock) Close() error                   { return nil }
+
+func (m *matrixMock) At(i int) color.Color {
+	if m.leds[i] == nil {
+		return color.Black
+	}
+
+	return m.leds[i]
+}
+
+func (m *matrixMock) Render() error {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	m.frames = append(m.frames, color.RGBAModel.Convert(m.leds[0]))
+	return nil
+}
+
+func uniform(c color.Color) image.Image {
+	return image.NewUniform(c)
+}
+
+func (s *PlaylistSuite) TestPlayFakeClock(c *C) {
+	clock := rgbmatrix.NewFakeClock(time.Date(2018, time.January, 1, 12, 0, 0, 0, time.UTC))
+
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+
+	m := &matrixMock{}
+	sc := NewScheduler(rgbmatrix.NewToolKit(m),
+		Image(uniform(red), 20*time.Second),
+		Image(uniform(blue), 20*time.Second),
+	)
+	sc.Sequential = true
+	sc.Clock = clock
+
+	quit := sc.Play()
+	for i := 0; i < 120; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(500 * time.Millisecond)
+	}
+
+	quit <- true
+
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	c.Assert(len(m.frames) >= 3, Equals, true)
+	c.Assert(m.frames[:3], DeepEquals, []color.Color{red, blue, red})
+}
+
+func (s *PlaylistSuite) TestNextWeights(c *C) {
+	sc := NewScheduler(nil, &Item{Weight: 9}, &Item{Weight: 1})
+	sc.Rand = rand.New(rand.NewSource(42))
+
+	var counts [2]int
+	for i := 0; i < 1000; i++ {
+		n := sc.Next(time.Now())
+		counts[n]++
+		sc.last = n
+	}
+
+	c.Assert(counts[0] > 850 && counts[0] < 950, Equals, true, Commentf("counts %v", counts))
+}
+
+func (s *PlaylistSuite) TestNextNoRepeat(c *C) {
+	sc := NewScheduler(nil, &Item{Weight: 9}, &Item{Weight: 1})
+	sc.Rand = rand.New(rand.NewSource(42))
+	sc.NoRepeat = true
+
+	sc.last = 0
+	for i := 0; i < 10; i++ {
+		n := sc.Next(time.Now())
+		c.Assert(n, Not(Equals), sc.last)
+		sc.last = n
+	}
+}
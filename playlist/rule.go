@@ -0,0 +1,211 @@
+package playlist
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule decides when an Item can be played
+type Rule interface {
+	Match(t time.Time) bool
+}
+
+// RuleFunc is an adapter to allow the use of ordinary functions as Rule
+type RuleFunc func(t time.Time) bool
+
+// Match calls f(t)
+func (f RuleFunc) Match(t time.Time) bool {
+	return f(t)
+}
+
+// Any returns a Rule matching when any of the given rules matches
+func Any(rules ...Rule) Rule {
+	return RuleFunc(func(t time.Time) bool {
+		for _, r := range rules {
+			if r.Match(t) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// All returns a Rule matching when all the given rules match
+func All(rules ...Rule) Rule {
+	return RuleFunc(func(t time.Time) bool {
+		for _, r := range rules {
+			if !r.Match(t) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// Window is a Rule matching a time-of-day window in the given days, From and
+// To are durations since midnight. If To is before From the window spans
+// midnight, eg. From 22h To 6h, Days always refers to the day of the matched
+// time.
+type Window struct {
+	// Days of the week when the window applies, every day if empty
+	Days []time.Weekday
+	// From is the start of the window, inclusive
+	From time.Duration
+	// To is the end of the window, exclusive
+	To time.Duration
+}
+
+// Match returns true if t is inside of the window
+func (w *Window) Match(t time.Time) bool {
+	if len(w.Days) != 0 {
+		var found bool
+		for _, d := range w.Days {
+			if d == t.Weekday() {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	// the wall clock is used, so the window isn't shifted on the days with a
+	// daylight saving time change
+	h, m, sec := t.Clock()
+	since := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(t.Nanosecond())
+	if w.To <= w.From {
+		return since >= w.From || since < w.To
+	}
+
+	return since >= w.From && since < w.To
+}
+
+// Cron is a Rule matching the minutes described by a cron expression
+type Cron struct {
+	minute, hour, dom, month, dow uint64
+	anyDOM, anyDOW                bool
+}
+
+// cronFields are the ranges of the five fields of a cron expression
+var cronFields = [5]struct{ min, max int }{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 7},  // day of week, 0 and 7 are Sunday
+}
+
+// ParseCron parses a cron expression with the standard five fields: minute,
+// hour, day of month, month and day of week. Every field supports "*", single
+// values, ranges "1-5", lists "1,3,5" and steps "*/15", "0-30/10" or
+// "5/10", from 5 to the max value.
+func ParseCron(expr string) (*Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, found %d", expr, len(fields))
+	}
+
+	var sets [5]uint64
+	for i, f := range fields {
+		set, err := parseCronField(f, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %s", expr, err)
+		}
+
+		sets[i] = set
+	}
+
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+
+	return &Cron{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		anyDOM: fields[2] == "*",
+		anyDOW: fields[4] == "*",
+	}, nil
+}
+
+// MustParseCron is like ParseCron but panics if the expression is invalid
+func MustParseCron(expr string) *Cron {
+	c, err := ParseCron(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+func parseCronField(f string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(f, ",") {
+		step, stepped := 1, false
+		if i := strings.Index(part, "/"); i != -1 {
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part[i+1:])
+			}
+
+			part, stepped = part[:i], true
+		}
+
+		from, to := min, max
+		if part != "*" {
+			var err error
+			bounds := strings.SplitN(part, "-", 2)
+			if from, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value %q", bounds[0])
+			}
+
+			// a single value with a step, as 5/10, means from the value
+			// to max
+			to = from
+			if stepped {
+				to = max
+			}
+
+			if len(bounds) == 2 {
+				if to, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid value %q", bounds[1])
+				}
+			}
+		}
+
+		if from < min || to > max || from > to {
+			return 0, fmt.Errorf("value %q out of range %d-%d", part, min, max)
+		}
+
+		for v := from; v <= to; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+
+	return set, nil
+}
+
+// Match returns true if the minute of t is matched by the expression, like
+// cron if both day of month and day of week are restricted, any of them
+// has to match
+func (c *Cron) Match(t time.Time) bool {
+	if c.minute&(1<<uint(t.Minute())) == 0 ||
+		c.hour&(1<<uint(t.Hour())) == 0 ||
+		c.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.anyDOM || c.anyDOW {
+		return dom && dow
+	}
+
+	return dom || dow
+}
@@ -0,0 +1,102 @@
+package playlist
+
+import (
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type RuleSuite struct{}
+
+var _ = Suite(&RuleSuite{})
+
+func date(day, hour, minute int) time.Time {
+	// 2018-01-01 is Monday
+	return time.Date(2018, time.January, day, hour, minute, 0, 0, time.UTC)
+}
+
+func (s *RuleSuite) TestWindow(c *C) {
+	lunch := &Window{From: 12 * time.Hour, To: 14 * time.Hour}
+	c.Assert(lunch.Match(date(1, 12, 0)), Equals, true)
+	c.Assert(lunch.Match(date(1, 13, 59)), Equals, true)
+	c.Assert(lunch.Match(date(1, 14, 0)), Equals, false)
+	c.Assert(lunch.Match(date(1, 11, 59)), Equals, false)
+}
+
+func (s *RuleSuite) TestWindowOvernight(c *C) {
+	night := &Window{From: 22 * time.Hour, To: 6 * time.Hour}
+	c.Assert(night.Match(date(1, 23, 0)), Equals, true)
+	c.Assert(night.Match(date(1, 5, 59)), Equals, true)
+	c.Assert(night.Match(date(1, 6, 0)), Equals, false)
+	c.Assert(night.Match(date(1, 12, 0)), Equals, false)
+}
+
+func (s *RuleSuite) TestWindowDST(c *C) {
+	loc, err := time.LoadLocation("America/New_York")
+	c.Assert(err, IsNil)
+
+	// the clocks are set forward on 2018-03-11 and back on 2018-11-04
+	night := &Window{From: 22 * time.Hour, To: 6 * time.Hour}
+	for _, day := range []time.Time{
+		time.Date(2018, time.March, 11, 0, 0, 0, 0, loc),
+		time.Date(2018, time.November, 4, 0, 0, 0, 0, loc),
+	} {
+		at := func(hour, minute int) time.Time {
+			return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+		}
+
+		c.Assert(night.Match(at(21, 30)), Equals, false, Commentf("day %s", day))
+		c.Assert(night.Match(at(22, 0)), Equals, true, Commentf("day %s", day))
+		c.Assert(night.Match(at(5, 59)), Equals, true, Commentf("day %s", day))
+		c.Assert(night.Match(at(6, 0)), Equals, false, Commentf("day %s", day))
+	}
+}
+
+func (s *RuleSuite) TestWindowDays(c *C) {
+	weekend := &Window{Days: []time.Weekday{time.Saturday, time.Sunday}, To: 24 * time.Hour}
+	c.Assert(weekend.Match(date(1, 10, 0)), Equals, false)
+	c.Assert(weekend.Match(date(6, 10, 0)), Equals, true)
+	c.Assert(weekend.Match(date(7, 0, 0)), Equals, true)
+}
+
+func (s *RuleSuite) TestCron(c *C) {
+	morning := MustParseCron("* 7-10 * * 1-5")
+	c.Assert(morning.Match(date(1, 7, 0)), Equals, true)
+	c.Assert(morning.Match(date(1, 10, 59)), Equals, true)
+	c.Assert(morning.Match(date(1, 11, 0)), Equals, false)
+	c.Assert(morning.Match(date(6, 8, 0)), Equals, false)
+
+	quarters := MustParseCron("*/15 * * * *")
+	c.Assert(quarters.Match(date(1, 3, 45)), Equals, true)
+	c.Assert(quarters.Match(date(1, 3, 46)), Equals, false)
+
+	sunday := MustParseCron("0 12 * * 7")
+	c.Assert(sunday.Match(date(7, 12, 0)), Equals, true)
+}
+
+func (s *RuleSuite) TestCronStepFrom(c *C) {
+	cron := MustParseCron("5/10 * * * *")
+	for _, m := range []int{5, 15, 25, 55} {
+		c.Assert(cron.Match(date(1, 3, m)), Equals, true, Commentf("minute %d", m))
+	}
+
+	c.Assert(cron.Match(date(1, 3, 0)), Equals, false)
+	c.Assert(cron.Match(date(1, 3, 10)), Equals, false)
+}
+
+func (s *RuleSuite) TestCronDayOfMonthOrWeek(c *C) {
+	cron := MustParseCron("0 0 15 * 1")
+	c.Assert(cron.Match(date(15, 0, 0)), Equals, true)
+	c.Assert(cron.Match(date(8, 0, 0)), Equals, true)
+	c.Assert(cron.Match(date(9, 0, 0)), Equals, false)
+}
+
+func (s *RuleSuite) TestParseCronErrors(c *C) {
+	for _, expr := range []string{"* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		_, err := ParseCron(expr)
+		c.Assert(err, NotNil, Commentf("expr %q", expr))
+	}
+}
@@ -0,0 +1,13 @@
+package rpc
+
+import (
+	"net/url"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+)
+
+func init() {
+	rgbmatrix.RegisterBackend("rpc", func(addr string, _ url.Values, _ *rgbmatrix.HardwareConfig) (rgbmatrix.Matrix, error) {
+		return NewClient("tcp", addr)
+	})
+}
@@ -0,0 +1,280 @@
+package script
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+	"github.com/mcuadros/go-rpi-rgb-led-matrix/internal/raster"
+	"go.starlark.net/starlark"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+// builtins returns the values predeclared for the script
+func (s *Script) builtins() starlark.StringDict {
+	return starlark.StringDict{
+		"width":   starlark.MakeInt(s.Size.X),
+		"height":  starlark.MakeInt(s.Size.Y),
+		"state":   starlark.NewDict(0),
+		"rgb":     starlark.NewBuiltin("rgb", rgb),
+		"hsv":     starlark.NewBuiltin("hsv", hsv),
+		"clear":   starlark.NewBuiltin("clear", s.clear),
+		"set":     starlark.NewBuiltin("set", s.set),
+		"fill":    starlark.NewBuiltin("fill", s.fill),
+		"line":    starlark.NewBuiltin("line", s.line),
+		"text":    starlark.NewBuiltin("text", s.text),
+		"measure": starlark.NewBuiltin("measure", s.measure),
+		"now":     starlark.NewBuiltin("now", s.now),
+		"clock":   starlark.NewBuiltin("clock", s.clock),
+	}
+}
+
+func rgb(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var r, g, bl int
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 3, &r, &g, &bl); err != nil {
+		return nil, err
+	}
+
+	return colorValue(color.RGBA{channel(r), channel(g), channel(bl), 255}), nil
+}
+
+func hsv(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var hv, sv, vv starlark.Value
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 3, &hv, &sv, &vv); err != nil {
+		return nil, err
+	}
+
+	h, ok1 := starlark.AsFloat(hv)
+	sat, ok2 := starlark.AsFloat(sv)
+	v, ok3 := starlark.AsFloat(vv)
+	if !ok1 || !ok2 || !ok3 {
+		return nil, fmt.Errorf("%s: arguments must be numbers", b.Name())
+	}
+
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+
+	c := v * sat
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, bl float64
+	switch int(h / 60) {
+	case 0:
+		r, g = c, x
+	case 1:
+		r, g = x, c
+	case 2:
+		g, bl = c, x
+	case 3:
+		g, bl = x, c
+	case 4:
+		r, bl = x, c
+	default:
+		r, bl = c, x
+	}
+
+	m := v - c
+	return colorValue(color.RGBA{
+		channel(int((r + m) * 255)),
+		channel(int((g + m) * 255)),
+		channel(int((bl + m) * 255)),
+		255,
+	}), nil
+}
+
+func (s *Script) clear(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var c starlark.Value = starlark.Tuple{starlark.MakeInt(0), starlark.MakeInt(0), starlark.MakeInt(0)}
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "color?", &c); err != nil {
+		return nil, err
+	}
+
+	col, err := toColor(c)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", b.Name(), err)
+	}
+
+	frame := s.image()
+	draw.Draw(frame, frame.Bounds(), image.NewUniform(col), image.ZP, draw.Src)
+	return starlark.None, nil
+}
+
+func (s *Script) set(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var x, y int
+	var c starlark.Value
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "x", &x, "y", &y, "color", &c); err != nil {
+		return nil, err
+	}
+
+	col, err := toColor(c)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", b.Name(), err)
+	}
+
+	s.image().Set(x, y, col)
+	return starlark.None, nil
+}
+
+func (s *Script) fill(t *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var x, y, w, h int
+	var c starlark.Value
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "x", &x, "y", &y, "w", &w, "h", &h, "color", &c); err != nil {
+		return nil, err
+	}
+
+	col, err := toColor(c)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", b.Name(), err)
+	}
+
+	if err := cancelled(t, b); err != nil {
+		return nil, err
+	}
+
+	frame := s.image()
+	r := image.Rect(x, y, x+w, y+h).Intersect(frame.Rect)
+	draw.Draw(frame, r, image.NewUniform(col), image.ZP, draw.Src)
+	return starlark.None, nil
+}
+
+func (s *Script) line(t *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var x0, y0, x1, y1 int
+	var c starlark.Value
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "x0", &x0, "y0", &y0, "x1", &x1, "y1", &y1, "color", &c); err != nil {
+		return nil, err
+	}
+
+	col, err := toColor(c)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", b.Name(), err)
+	}
+
+	if err := cancelled(t, b); err != nil {
+		return nil, err
+	}
+
+	// the line is clipped to the frame, so it's bounded by its size
+	raster.Line(s.image(), image.Pt(x0, y0), image.Pt(x1, y1), col)
+	return starlark.None, nil
+}
+
+func (s *Script) text(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var text string
+	var c, x starlark.Value = nil, starlark.None
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "s", &text, "color", &c, "x?", &x); err != nil {
+		return nil, err
+	}
+
+	col, err := toColor(c)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", b.Name(), err)
+	}
+
+	pos := (s.Size.X - rgbmatrix.MeasureText(text, s.face())) / 2
+	if x != starlark.None {
+		if err := starlark.AsInt(x, &pos); err != nil {
+			return nil, fmt.Errorf("%s: x: %s", b.Name(), err)
+		}
+	}
+
+	rgbmatrix.DrawText(s.image(), text, s.face(), col, pos)
+	return starlark.None, nil
+}
+
+func (s *Script) measure(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var text string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &text); err != nil {
+		return nil, err
+	}
+
+	return starlark.MakeInt(rgbmatrix.MeasureText(text, s.face())), nil
+}
+
+func (s *Script) face() font.Face {
+	if s.Face == nil {
+		return basicfont.Face7x13
+	}
+
+	return s.Face
+}
+
+func (s *Script) now(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+
+	return starlark.Float(float64(s.clockOrSystem().Now().UnixNano()) / float64(time.Second)), nil
+}
+
+func (s *Script) clock(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+
+	h, m, sec := s.clockOrSystem().Now().Clock()
+	return starlark.Tuple{starlark.MakeInt(h), starlark.MakeInt(m), starlark.MakeInt(sec)}, nil
+}
+
+func colorValue(c color.RGBA) starlark.Value {
+	return starlark.Tuple{starlark.MakeInt(int(c.R)), starlark.MakeInt(int(c.G)), starlark.MakeInt(int(c.B))}
+}
+
+// toColor converts a tuple of (r, g, b), a "#rrggbb" string or a 0xrrggbb int
+// to a color
+func toColor(v starlark.Value) (color.Color, error) {
+	switch v := v.(type) {
+	case starlark.Tuple:
+		if len(v) != 3 {
+			return nil, fmt.Errorf("color tuple must have 3 elements, got %d", len(v))
+		}
+
+		var c [3]int
+		for i, e := range v {
+			if err := starlark.AsInt(e, &c[i]); err != nil {
+				return nil, fmt.Errorf("invalid color: %s", err)
+			}
+		}
+
+		return color.RGBA{channel(c[0]), channel(c[1]), channel(c[2]), 255}, nil
+	case starlark.String:
+		hex := strings.TrimPrefix(string(v), "#")
+		n, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || len(hex) != 6 {
+			return nil, fmt.Errorf("invalid color %q", string(v))
+		}
+
+		return rgbColor(int(n)), nil
+	case starlark.Int:
+		var n int
+		if err := starlark.AsInt(v, &n); err != nil {
+			return nil, fmt.Errorf("invalid color: %s", err)
+		}
+
+		return rgbColor(n), nil
+	}
+
+	return nil, fmt.Errorf("invalid color type %s", v.Type())
+}
+
+func rgbColor(n int) color.RGBA {
+	return color.RGBA{uint8(n >> 16), uint8(n >> 8), uint8(n), 255}
+}
+
+func channel(v int) uint8 {
+	if v < 0 {
+		return 0
+	}
+
+	if v > 255 {
+		return 255
+	}
+
+	return uint8(v)
+}
@@ -0,0 +1,319 @@
+// Package script runs Starlark scripts as animations, allowing to write the
+// content of a matrix without writing Go. Starlark is a small dialect of
+// Python, see https://github.com/google/starlark-go for the language.
+//
+// The top level code of the script is executed once, when it is loaded, and
+// the function frame is called for every frame with the seconds elapsed since
+// the start of the animation. The frame is drawn using the builtins and kept
+// between calls, frame may return the seconds until the next frame or False
+// to end the animation:
+//
+//	def frame(t):
+//	    clear()
+//	    x = int(t * 10) % width
+//	    line(x, 0, x, height - 1, hsv(t * 36, 1, 1))
+//	    text("%02d:%02d" % clock()[:2], rgb(255, 255, 255))
+//
+// The builtins available are:
+//
+//	width, height                       size of the frame
+//	state                               dict kept between frames
+//	rgb(r, g, b), hsv(h, s, v)          return a color, h in degrees
+//	clear(color=black)                  fills the frame
+//	set(x, y, color)                    sets a pixel
+//	fill(x, y, w, h, color)             fills a rectangle
+//	line(x0, y0, x1, y1, color)         draws a line
+//	text(s, color, x=centered)          draws text vertically centered
+//	measure(s)                          returns the width of a text
+//	now()                               returns the unix time in seconds
+//	clock()                             returns the local (hour, min, sec)
+//	math, time                          the Starlark math and time modules
+//
+// Colors are tuples of (r, g, b), strings as "#rrggbb" or ints as 0xrrggbb.
+package script
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+	"go.starlark.net/lib/math"
+	starlarktime "go.starlark.net/lib/time"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+	"golang.org/x/image/font"
+)
+
+const (
+	// DefaultFrameRate is the frames per second rendered when the frame
+	// function doesn't return the time until the next frame
+	DefaultFrameRate = 30
+	// DefaultMaxSteps is the max number of Starlark steps executed by the
+	// script when loaded and in every frame if no MaxSteps is given
+	DefaultMaxSteps = 10000000
+	// DefaultTimeout is the max time the script can run when loaded and in
+	// every frame if no Timeout is given
+	DefaultTimeout = time.Second
+)
+
+// WatchInterval is the interval between checks of the script file when Watch
+// is enabled
+var WatchInterval = 500 * time.Millisecond
+
+// ErrNoFrame is returned when a script doesn't define a frame function
+var ErrNoFrame = errors.New("script: frame function not defined")
+
+// Script is an Animation running a Starlark script
+type Script struct {
+	// Path of the script file
+	Path string
+	// Size of the frames
+	Size image.Point
+	// FrameRate is the frames per second rendered, DefaultFrameRate if zero
+	FrameRate int
+	// MaxSteps is the max number of steps executed in every frame,
+	// DefaultMaxSteps if zero
+	MaxSteps uint64
+	// Timeout is the max time every frame can take, DefaultTimeout if zero
+	Timeout time.Duration
+	// Face is the font used by text, basicfont.Face7x13 if nil
+	Face font.Face
+	// Watch reloads the script when the file changes. The errors loading or
+	// running the script don't end the animation, the last frame is shown
+	// until the file changes again and the error is returned by Err
+	Watch bool
+	// Clock used to schedule the frames and by now and clock, SystemClock if
+	// nil. The Timeout is always measured in real time.
+	Clock rgbmatrix.Clock
+
+	m       sync.Mutex
+	err     error
+	fn      starlark.Callable
+	failed  bool
+	modTime time.Time
+	checked time.Time
+	start   time.Time
+	frame   *image.RGBA
+}
+
+// NewScript loads the script at the given path, returning a Script rendering
+// frames of the given size
+func NewScript(path string, size image.Point) (*Script, error) {
+	s := &Script{Path: path, Size: size}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Next calls the frame function of the script returning the frame drawn
+func (s *Script) Next() (image.Image, <-chan time.Time, error) {
+	if s.Watch {
+		s.reload()
+	} else if s.fn == nil {
+		if err := s.load(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if s.fn == nil || s.failed {
+		return s.image(), s.clockOrSystem().After(WatchInterval), nil
+	}
+
+	now := s.clockOrSystem().Now()
+	if s.start.IsZero() {
+		s.start = now
+	}
+
+	v, err := s.call(s.fn, starlark.Float(now.Sub(s.start).Seconds()))
+	if err != nil {
+		if !s.Watch {
+			return nil, nil, err
+		}
+
+		s.setErr(err)
+		s.failed = true
+		return s.image(), s.clockOrSystem().After(WatchInterval), nil
+	}
+
+	delay := s.interval()
+	switch v := v.(type) {
+	case starlark.Bool:
+		if !v {
+			return nil, nil, io.EOF
+		}
+	case starlark.Int, starlark.Float:
+		f, _ := starlark.AsFloat(v)
+		delay = time.Duration(f * float64(time.Second))
+	}
+
+	return s.image(), s.clockOrSystem().After(delay - s.clockOrSystem().Now().Sub(now)), nil
+}
+
+// SetClock sets the Clock of the Script
+func (s *Script) SetClock(c rgbmatrix.Clock) {
+	s.Clock = c
+}
+
+func (s *Script) clockOrSystem() rgbmatrix.Clock {
+	return rgbmatrix.ClockOrSystem(s.Clock)
+}
+
+// Err returns the last error loading or running the script when Watch is
+// enabled, nil once the script is reloaded successfully
+func (s *Script) Err() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.err
+}
+
+func (s *Script) setErr(err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.err = err
+}
+
+// reload loads the script again if the file was modified since the last load
+func (s *Script) reload() {
+	now := s.clockOrSystem().Now()
+	if now.Sub(s.checked) < WatchInterval {
+		return
+	}
+
+	s.checked = now
+	fi, err := os.Stat(s.Path)
+	if err != nil {
+		s.setErr(err)
+		return
+	}
+
+	if fi.ModTime().Equal(s.modTime) {
+		return
+	}
+
+	if err := s.load(); err != nil {
+		s.setErr(err)
+		return
+	}
+
+	s.setErr(nil)
+	s.failed = false
+}
+
+// load reads, executes and replaces the current version of the script
+func (s *Script) load() error {
+	fi, err := os.Stat(s.Path)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.ReadFile(s.Path)
+	if err != nil {
+		return err
+	}
+
+	s.modTime = fi.ModTime()
+	s.start = time.Time{}
+
+	predeclared := s.builtins()
+	predeclared["math"] = math.Module
+	predeclared["time"] = starlarktime.Module
+
+	var globals starlark.StringDict
+	err = s.run(func(t *starlark.Thread) error {
+		globals, err = starlark.ExecFileOptions(&syntax.FileOptions{}, t, s.Path, src, predeclared)
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	fn, ok := globals["frame"].(starlark.Callable)
+	if !ok {
+		return ErrNoFrame
+	}
+
+	s.fn = fn
+	return nil
+}
+
+// call calls fn with the given args, with the limits of a frame
+func (s *Script) call(fn starlark.Callable, args ...starlark.Value) (starlark.Value, error) {
+	var v starlark.Value
+	err := s.run(func(t *starlark.Thread) error {
+		var err error
+		v, err = starlark.Call(t, fn, args, nil)
+		return err
+	})
+
+	return v, err
+}
+
+// run calls f with a new thread limited by MaxSteps and Timeout
+func (s *Script) run(f func(t *starlark.Thread) error) error {
+	t := &starlark.Thread{Name: s.Path}
+
+	steps := s.MaxSteps
+	if steps == 0 {
+		steps = DefaultMaxSteps
+	}
+
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	// the timeout limits the real time used, so it ignores the Clock
+	done := make(chan struct{})
+	t.SetLocal(doneKey, done)
+	t.SetMaxExecutionSteps(steps)
+	timer := time.AfterFunc(timeout, func() {
+		t.Cancel(fmt.Sprintf("timeout after %s", timeout))
+		close(done)
+	})
+
+	defer timer.Stop()
+	return f(t)
+}
+
+// doneKey is the thread local of the chan closed when the Timeout expires
+const doneKey = "done"
+
+// cancelled returns an error if the Timeout of the thread expired, checked by
+// the builtins doing long operations, as the steps of the thread are only
+// counted running Starlark code
+func cancelled(t *starlark.Thread, b *starlark.Builtin) error {
+	done, _ := t.Local(doneKey).(chan struct{})
+	select {
+	case <-done:
+		return fmt.Errorf("%s: cancelled by timeout", b.Name())
+	default:
+		return nil
+	}
+}
+
+// image returns the frame, allocated again if the Size changed
+func (s *Script) image() *image.RGBA {
+	if s.frame == nil || s.frame.Rect.Size() != s.Size {
+		s.frame = image.NewRGBA(image.Rectangle{Max: s.Size})
+	}
+
+	return s.frame
+}
+
+func (s *Script) interval() time.Duration {
+	if s.FrameRate <= 0 {
+		return time.Second / DefaultFrameRate
+	}
+
+	return time.Second / time.Duration(s.FrameRate)
+}
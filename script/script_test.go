@@ -0,0 +1,149 @@
+package script
+
+import (
+	"image"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type ScriptSuite struct {
+	dir string
+}
+
+var _ = Suite(&ScriptSuite{})
+
+func (s *ScriptSuite) SetUpTest(c *C) {
+	s.dir = c.MkDir()
+}
+
+func (s *ScriptSuite) write(c *C, src string) string {
+	path := filepath.Join(s.dir, "test.star")
+	err := os.WriteFile(path, []byte(src), 0644)
+	c.Assert(err, IsNil)
+
+	return path
+}
+
+func (s *ScriptSuite) TestDraw(c *C) {
+	path := s.write(c, `
+def frame(t):
+    clear("#000080")
+    set(0, 0, rgb(255, 0, 0))
+    fill(1, 1, 2, 2, 0x00ff00)
+    line(0, 3, 3, 3, hsv(0, 0, 1))
+    state["frames"] = state.get("frames", 0) + 1
+    return 0.01
+`)
+
+	sc, err := NewScript(path, image.Pt(4, 4))
+	c.Assert(err, IsNil)
+
+	img, next, err := sc.Next()
+	c.Assert(err, IsNil)
+	c.Assert(img.Bounds(), Equals, image.Rect(0, 0, 4, 4))
+	c.Assert(img.At(0, 0), Equals, color.RGBA{255, 0, 0, 255})
+	c.Assert(img.At(1, 0), Equals, color.RGBA{0, 0, 128, 255})
+	c.Assert(img.At(2, 2), Equals, color.RGBA{0, 255, 0, 255})
+	c.Assert(img.At(3, 3), Equals, color.RGBA{255, 255, 255, 255})
+
+	select {
+	case <-next:
+	case <-time.After(time.Second):
+		c.Fatal("next frame not scheduled")
+	}
+
+	_, _, err = sc.Next()
+	c.Assert(err, IsNil)
+}
+
+func (s *ScriptSuite) TestEnd(c *C) {
+	path := s.write(c, `
+def frame(t):
+    return False
+`)
+
+	sc, err := NewScript(path, image.Pt(4, 4))
+	c.Assert(err, IsNil)
+
+	_, _, err = sc.Next()
+	c.Assert(err, Equals, io.EOF)
+}
+
+func (s *ScriptSuite) TestNoFrame(c *C) {
+	_, err := NewScript(s.write(c, "x = 1\n"), image.Pt(4, 4))
+	c.Assert(err, Equals, ErrNoFrame)
+}
+
+func (s *ScriptSuite) TestMaxSteps(c *C) {
+	path := s.write(c, `
+def frame(t):
+    for i in range(1000000):
+        pass
+`)
+
+	sc, err := NewScript(path, image.Pt(4, 4))
+	c.Assert(err, IsNil)
+	sc.MaxSteps = 1000
+
+	_, _, err = sc.Next()
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(err.Error(), "too many steps"), Equals, true)
+}
+
+func (s *ScriptSuite) TestWatch(c *C) {
+	defer func(d time.Duration) { WatchInterval = d }(WatchInterval)
+	WatchInterval = 0
+
+	path := s.write(c, "def frame(t):\n    clear((255, 0, 0))\n")
+	sc, err := NewScript(path, image.Pt(1, 1))
+	c.Assert(err, IsNil)
+	sc.Watch = true
+
+	img, _, err := sc.Next()
+	c.Assert(err, IsNil)
+	c.Assert(img.At(0, 0), Equals, color.RGBA{255, 0, 0, 255})
+
+	s.write(c, "def frame(t):\n    clear(undefined)\n")
+	c.Assert(os.Chtimes(path, time.Now(), time.Now().Add(time.Second)), IsNil)
+
+	img, _, err = sc.Next()
+	c.Assert(err, IsNil)
+	c.Assert(img.At(0, 0), Equals, color.RGBA{255, 0, 0, 255})
+	c.Assert(sc.Err(), NotNil)
+
+	s.write(c, "def frame(t):\n    clear((0, 0, 255))\n")
+	c.Assert(os.Chtimes(path, time.Now(), time.Now().Add(2*time.Second)), IsNil)
+
+	img, _, err = sc.Next()
+	c.Assert(err, IsNil)
+	c.Assert(img.At(0, 0), Equals, color.RGBA{0, 0, 255, 255})
+	c.Assert(sc.Err(), IsNil)
+}
+
+func (s *ScriptSuite) TestClip(c *C) {
+	path := s.write(c, `
+def frame(t):
+    line(-2000000000, 1, 2000000000, 1, "#ff0000")
+    fill(-2000000000, 2, 4000000000, 1, "#00ff00")
+    line(5, 5, 9, 9, "#0000ff")
+`)
+
+	sc, err := NewScript(path, image.Pt(4, 4))
+	c.Assert(err, IsNil)
+
+	img, _, err := sc.Next()
+	c.Assert(err, IsNil)
+	c.Assert(img.At(0, 1), Equals, color.RGBA{255, 0, 0, 255})
+	c.Assert(img.At(3, 1), Equals, color.RGBA{255, 0, 0, 255})
+	c.Assert(img.At(0, 2), Equals, color.RGBA{0, 255, 0, 255})
+	c.Assert(img.At(3, 3), Equals, color.RGBA{0, 0, 0, 0})
+}
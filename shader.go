@@ -0,0 +1,173 @@
+package rgbmatrix
+
+import (
+	"image"
+	"image/color"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// DefaultShaderFrameRate is the frames per second rendered by a Shader when no
+// FrameRate is given
+const DefaultShaderFrameRate = 30
+
+// ShaderFunc returns the color of the pixel at x, y at the time t, elapsed
+// since the start of the Shader, a nil color is drawn transparent. It is
+// called concurrently from several goroutines, so it should not modify any
+// shared state.
+type ShaderFunc func(x, y int, t time.Duration) color.Color
+
+// Shader is an Animation evaluating a ShaderFunc for every pixel of every
+// frame, the rows of the frame are split across several goroutines. The
+// frames are scheduled at a fixed frame rate, if a frame takes longer than the
+// interval between frames, the frames that can't be rendered are skipped and
+// the Shader is reported as late.
+//
+// Use it as any other Animation, with ToolKit.PlayAnimation:
+//
+//	s := rgbmatrix.NewShader(c.Bounds().Size(), func(x, y int, t time.Duration) color.Color {
+//		return color.Gray{Y: uint8(x*8 + int(t/time.Millisecond)/10)}
+//	})
+//	tk.PlayAnimation(s)
+type Shader struct {
+	// Func is the function evaluated for every pixel
+	Func ShaderFunc
+	// Size of the frames
+	Size image.Point
+	// FrameRate is the frames per second rendered, DefaultShaderFrameRate if
+	// zero
+	FrameRate int
+	// Workers is the number of goroutines evaluating the frame,
+	// runtime.NumCPU if zero
+	Workers int
+	// OnLate if present is called every time a frame is rendered slower than
+	// the frame rate, with the time took by the frame and the number of
+	// frames skipped
+	OnLate func(took time.Duration, skipped int)
+
+	Clocked
+	m       sync.Mutex
+	start   time.Time
+	frame   int
+	stats   ShaderStats
+	buffers [2]*image.RGBA
+}
+
+// ShaderStats are the statistics of the frames rendered by a Shader
+type ShaderStats struct {
+	// Frames is the number of frames rendered
+	Frames int
+	// Late is the number of frames slower than the frame rate
+	Late int
+	// Skipped is the number of frames skipped to catch up with the frame rate
+	Skipped int
+	// Last is the time took by the last frame
+	Last time.Duration
+}
+
+// NewShader returns a new Shader of the given size evaluating f
+func NewShader(size image.Point, f ShaderFunc) *Shader {
+	return &Shader{Func: f, Size: size}
+}
+
+// Next evaluates the next frame, the frame is shown until the time of the
+// next frame in the schedule
+func (s *Shader) Next() (image.Image, <-chan time.Time, error) {
+	interval := s.interval()
+
+	clock := s.Clock()
+	now := clock.Now()
+	if s.start.IsZero() {
+		s.start = now
+	}
+
+	// two buffers are used, since the previous frame may be still in use
+	frame := s.buffers[s.frame%2]
+	if frame == nil || frame.Rect.Size() != s.Size {
+		frame = image.NewRGBA(image.Rectangle{Max: s.Size})
+		s.buffers[s.frame%2] = frame
+	}
+
+	s.Draw(frame, time.Duration(s.frame)*interval)
+	took := clock.Now().Sub(now)
+
+	// the next frame is the first one in the schedule not already passed
+	next := s.frame + 1
+	if elapsed := clock.Now().Sub(s.start); time.Duration(next)*interval < elapsed {
+		next = int(elapsed/interval) + 1
+	}
+
+	skipped := next - s.frame - 1
+	s.frame = next
+
+	s.m.Lock()
+	s.stats.Frames++
+	s.stats.Last = took
+	if took > interval {
+		s.stats.Late++
+		s.stats.Skipped += skipped
+	}
+	s.m.Unlock()
+
+	if took > interval && s.OnLate != nil {
+		s.OnLate(took, skipped)
+	}
+
+	return frame, clock.After(s.start.Add(time.Duration(next) * interval).Sub(clock.Now())), nil
+}
+
+// Draw evaluates the shader for every pixel of dst at the time t
+func (s *Shader) Draw(dst *image.RGBA, t time.Duration) {
+	r := dst.Bounds()
+	rows := make(chan int, r.Dy())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		rows <- y
+	}
+
+	close(rows)
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.workers(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for y := range rows {
+				for x := r.Min.X; x < r.Max.X; x++ {
+					c := s.Func(x-r.Min.X, y-r.Min.Y, t)
+					if c == nil {
+						c = color.Transparent
+					}
+
+					dst.Set(x, y, c)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// Stats returns the statistics of the frames rendered so far
+func (s *Shader) Stats() ShaderStats {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.stats
+}
+
+func (s *Shader) interval() time.Duration {
+	if s.FrameRate <= 0 {
+		return time.Second / DefaultShaderFrameRate
+	}
+
+	return time.Second / time.Duration(s.FrameRate)
+}
+
+func (s *Shader) workers() int {
+	if s.Workers <= 0 {
+		return runtime.NumCPU()
+	}
+
+	return s.Workers
+}
@@ -0,0 +1,97 @@
+package rgbmatrix
+
+import (
+	"image"
+	"image/color"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type ShaderSuite struct{}
+
+var _ = Suite(&ShaderSuite{})
+
+func (s *ShaderSuite) TestDraw(c *C) {
+	sh := NewShader(image.Pt(8, 8), func(x, y int, t time.Duration) color.Color {
+		return color.RGBA{uint8(x), uint8(y), uint8(t / time.Second), 255}
+	})
+	sh.Workers = 3
+
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	sh.Draw(img, 2*time.Second)
+
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			c.Assert(img.At(x, y), Equals, color.RGBA{uint8(x), uint8(y), 2, 255})
+		}
+	}
+}
+
+func (s *ShaderSuite) TestDrawNil(c *C) {
+	sh := NewShader(image.Pt(2, 2), func(x, y int, t time.Duration) color.Color {
+		if x == 0 {
+			return nil
+		}
+
+		return red
+	})
+
+	img := newUniformRGBA(2, 2, blue)
+	sh.Draw(img, 0)
+	c.Assert(img.At(0, 0), Equals, color.RGBA{})
+	c.Assert(img.At(1, 1), Equals, red)
+}
+
+func (s *ShaderSuite) TestNext(c *C) {
+	clock := NewFakeClock(epoch)
+
+	var times []time.Duration
+	sh := NewShader(image.Pt(1, 1), func(x, y int, t time.Duration) color.Color {
+		times = append(times, t)
+		return red
+	})
+	sh.FrameRate = 10
+	sh.SetClock(clock)
+
+	for i := 0; i < 3; i++ {
+		img, next, err := sh.Next()
+		c.Assert(err, IsNil)
+		c.Assert(img.Bounds(), Equals, image.Rect(0, 0, 1, 1))
+		c.Assert(img.At(0, 0), Equals, red)
+
+		clock.Advance(100 * time.Millisecond)
+		<-next
+	}
+
+	c.Assert(times, DeepEquals, []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond})
+	c.Assert(sh.Stats().Frames, Equals, 3)
+}
+
+func (s *ShaderSuite) TestLate(c *C) {
+	clock := NewFakeClock(epoch)
+
+	var reported int
+	sh := NewShader(image.Pt(1, 1), func(x, y int, t time.Duration) color.Color {
+		clock.Advance(35 * time.Millisecond)
+		return red
+	})
+	sh.FrameRate = 100
+	sh.Workers = 1
+	sh.SetClock(clock)
+	sh.OnLate = func(took time.Duration, skipped int) {
+		reported += skipped
+	}
+
+	_, next, err := sh.Next()
+	c.Assert(err, IsNil)
+
+	clock.Advance(5 * time.Millisecond)
+	<-next
+
+	stats := sh.Stats()
+	c.Assert(stats.Late, Equals, 1)
+	c.Assert(stats.Skipped, Equals, 3)
+	c.Assert(stats.Last, Equals, 35*time.Millisecond)
+	c.Assert(reported, Equals, 3)
+}
@@ -0,0 +1,126 @@
+package sprite
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"time"
+)
+
+// NewAtlasSheet returns a Sheet with the frames described by a JSON atlas
+// read from r, in the format exported by TexturePacker and Aseprite, with the
+// frames as an array or as an object by name:
+//
+//	{
+//	  "frames": {
+//	    "walk 0": {"frame": {"x": 0, "y": 0, "w": 16, "h": 16}, "duration": 100},
+//	    "walk 1": {"frame": {"x": 16, "y": 0, "w": 16, "h": 16}, "duration": 150}
+//	  },
+//	  "meta": {
+//	    "frameTags": [{"name": "walk", "from": 0, "to": 1, "direction": "forward"}]
+//	  }
+//	}
+//
+// The duration is in milliseconds, the frame tags are defined as clips.
+func NewAtlasSheet(img image.Image, r io.Reader) (*Sheet, error) {
+	var a atlas
+	if err := json.NewDecoder(r).Decode(&a); err != nil {
+		return nil, fmt.Errorf("sprite: invalid atlas: %s", err)
+	}
+
+	s := newSheet(img)
+	for _, f := range a.Frames {
+		s.Frames = append(s.Frames, Frame{
+			Name:     f.Filename,
+			Bounds:   image.Rect(f.Frame.X, f.Frame.Y, f.Frame.X+f.Frame.W, f.Frame.Y+f.Frame.H),
+			Duration: time.Duration(f.Duration) * time.Millisecond,
+		})
+	}
+
+	for _, t := range a.Meta.FrameTags {
+		if t.From < 0 || t.To < t.From || t.To >= len(s.Frames) {
+			return nil, fmt.Errorf("sprite: invalid range of frame tag %q", t.Name)
+		}
+
+		var frames []int
+		for i := t.From; i <= t.To; i++ {
+			frames = append(frames, i)
+		}
+
+		switch t.Direction {
+		case "reverse":
+			for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+				frames[i], frames[j] = frames[j], frames[i]
+			}
+		case "pingpong":
+			for i := len(frames) - 2; i > 0; i-- {
+				frames = append(frames, frames[i])
+			}
+		}
+
+		if _, err := s.Define(t.Name, frames); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+type atlas struct {
+	Frames atlasFrames `json:"frames"`
+	Meta   struct {
+		FrameTags []struct {
+			Name      string `json:"name"`
+			From      int    `json:"from"`
+			To        int    `json:"to"`
+			Direction string `json:"direction"`
+		} `json:"frameTags"`
+	} `json:"meta"`
+}
+
+type atlasFrame struct {
+	Filename string `json:"filename"`
+	Frame    struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+		W int `json:"w"`
+		H int `json:"h"`
+	} `json:"frame"`
+	Duration int `json:"duration"`
+}
+
+// atlasFrames decodes the frames as an array or as an object, keeping the
+// order of the keys of the object
+type atlasFrames []atlasFrame
+
+func (f *atlasFrames) UnmarshalJSON(data []byte) error {
+	var list []atlasFrame
+	if err := json.Unmarshal(data, &list); err == nil {
+		*f = list
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return fmt.Errorf("frames must be an array or an object")
+	}
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		var frame atlasFrame
+		if err := dec.Decode(&frame); err != nil {
+			return err
+		}
+
+		frame.Filename, _ = key.(string)
+		*f = append(*f, frame)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package sprite
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+)
+
+// DefaultFrameRate is the frames per second rendered by a Scene when no
+// FrameRate is given
+const DefaultFrameRate = 30
+
+// Scene is an Animation rendering a tile map and a list of sprites over it.
+// The sprites are drawn in order, at their position in the frame.
+type Scene struct {
+	// Size of the frames
+	Size image.Point
+	// Background color, black if nil
+	Background color.Color
+	// Map drawn under the sprites, optional
+	Map *TileMap
+	// Sprites drawn over the map
+	Sprites []*Sprite
+	// FrameRate is the frames per second rendered, DefaultFrameRate if zero
+	FrameRate int
+	// Update if present is called before every frame, with the time elapsed
+	// since the previous frame, to move the sprites or scroll the map. If an
+	// error is returned, it is returned by Next, io.EOF ends the scene.
+	Update func(s *Scene, elapsed time.Duration) error
+
+	clock rgbmatrix.Clock
+	last  time.Time
+	frame *image.RGBA
+}
+
+// NewScene returns a new Scene of the given size
+func NewScene(size image.Point) *Scene {
+	return &Scene{Size: size}
+}
+
+// Add adds the given sprites to the scene
+func (s *Scene) Add(sprites ...*Sprite) {
+	for _, sp := range sprites {
+		if s.clock != nil {
+			sp.Clock = s.clock
+		}
+	}
+
+	s.Sprites = append(s.Sprites, sprites...)
+}
+
+// SetClock sets the Clock of the scene and its sprites
+func (s *Scene) SetClock(c rgbmatrix.Clock) {
+	s.clock = c
+	for _, sp := range s.Sprites {
+		sp.Clock = c
+	}
+}
+
+// Next calls Update and renders the frame
+func (s *Scene) Next() (image.Image, <-chan time.Time, error) {
+	clock := rgbmatrix.ClockOrSystem(s.clock)
+
+	now := clock.Now()
+	if s.last.IsZero() {
+		s.last = now
+	}
+
+	if s.Update != nil {
+		if err := s.Update(s, now.Sub(s.last)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	s.last = now
+	if s.frame == nil || s.frame.Rect.Size() != s.Size {
+		s.frame = image.NewRGBA(image.Rectangle{Max: s.Size})
+	}
+
+	bg := s.Background
+	if bg == nil {
+		bg = color.Black
+	}
+
+	draw.Draw(s.frame, s.frame.Bounds(), image.NewUniform(bg), image.ZP, draw.Src)
+	if s.Map != nil {
+		s.Map.Draw(s.frame)
+	}
+
+	for _, sp := range s.Sprites {
+		sp.Draw(s.frame)
+	}
+
+	return s.frame, clock.After(s.interval() - clock.Now().Sub(now)), nil
+}
+
+func (s *Scene) interval() time.Duration {
+	if s.FrameRate <= 0 {
+		return time.Second / DefaultFrameRate
+	}
+
+	return time.Second / time.Duration(s.FrameRate)
+}
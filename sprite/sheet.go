@@ -0,0 +1,178 @@
+// Package sprite provides sprite sheets, animated sprites and scrolling tile
+// maps, to build small pixel-art scenes rendered as an Animation.
+package sprite
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"time"
+)
+
+// DefaultFrameDuration is the duration of the frames without duration, as the
+// frames of a grid sheet
+const DefaultFrameDuration = 100 * time.Millisecond
+
+// Frame is a region of the sheet image
+type Frame struct {
+	// Name of the frame, empty in grid sheets
+	Name string
+	// Bounds of the frame in the sheet image
+	Bounds image.Rectangle
+	// Duration of the frame when used in a Clip, DefaultFrameDuration if zero
+	Duration time.Duration
+}
+
+// Clip is a named animation, a sequence of frames of a sheet
+type Clip struct {
+	// Name of the clip
+	Name string
+	// Frames are the indexes of the frames in the sheet
+	Frames []int
+	// Durations of every frame, same length as Frames
+	Durations []time.Duration
+	// Loop plays the clip again once it ends
+	Loop bool
+}
+
+// Duration returns the total duration of the clip
+func (c *Clip) Duration() time.Duration {
+	var d time.Duration
+	for _, fd := range c.Durations {
+		d += fd
+	}
+
+	return d
+}
+
+// At returns the frame index of the sheet at the given elapsed time since the
+// start of the clip, and if the clip is done
+func (c *Clip) At(elapsed time.Duration) (int, bool) {
+	if len(c.Frames) == 0 {
+		return -1, true
+	}
+
+	total := c.Duration()
+	if total <= 0 {
+		return c.Frames[0], !c.Loop
+	}
+
+	if c.Loop {
+		elapsed %= total
+	} else if elapsed >= total {
+		return c.Frames[len(c.Frames)-1], true
+	}
+
+	for i, d := range c.Durations {
+		if elapsed < d {
+			return c.Frames[i], false
+		}
+
+		elapsed -= d
+	}
+
+	return c.Frames[len(c.Frames)-1], false
+}
+
+// Sheet is an image containing several frames
+type Sheet struct {
+	// Frames of the sheet
+	Frames []Frame
+	// Clips are the animations defined in the sheet by name
+	Clips map[string]*Clip
+
+	img subImager
+}
+
+type subImager interface {
+	image.Image
+	SubImage(r image.Rectangle) image.Image
+}
+
+func newSheet(img image.Image) *Sheet {
+	si, ok := img.(subImager)
+	if !ok {
+		rgba := image.NewRGBA(img.Bounds())
+		draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+		si = rgba
+	}
+
+	return &Sheet{img: si, Clips: make(map[string]*Clip)}
+}
+
+// NewGridSheet returns a Sheet splitting the image in a grid of frames of the
+// given size, the frames are indexed from left to right and top to bottom
+func NewGridSheet(img image.Image, size image.Point) *Sheet {
+	s := newSheet(img)
+	if size.X <= 0 || size.Y <= 0 {
+		return s
+	}
+
+	b := img.Bounds()
+	for y := b.Min.Y; y+size.Y <= b.Max.Y; y += size.Y {
+		for x := b.Min.X; x+size.X <= b.Max.X; x += size.X {
+			s.Frames = append(s.Frames, Frame{
+				Bounds: image.Rectangle{image.Pt(x, y), image.Pt(x+size.X, y+size.Y)},
+			})
+		}
+	}
+
+	return s
+}
+
+// Image returns the image of the frame i, with the bounds of the frame, or a
+// transparent image if the frame doesn't exist
+func (s *Sheet) Image(i int) image.Image {
+	if i < 0 || i >= len(s.Frames) {
+		return image.Transparent
+	}
+
+	return s.img.SubImage(s.Frames[i].Bounds)
+}
+
+// Lookup returns the index of the frame with the given name
+func (s *Sheet) Lookup(name string) (int, bool) {
+	for i, f := range s.Frames {
+		if f.Name == name {
+			return i, true
+		}
+	}
+
+	return -1, false
+}
+
+// Define defines a looping clip with the given frames. If durations is nil
+// the duration of every frame in the sheet is used, a single duration is used
+// for all the frames, otherwise the durations should have the length of
+// frames.
+func (s *Sheet) Define(name string, frames []int, durations ...time.Duration) (*Clip, error) {
+	for _, i := range frames {
+		if i < 0 || i >= len(s.Frames) {
+			return nil, fmt.Errorf("sprite: frame %d out of range in clip %q", i, name)
+		}
+	}
+
+	c := &Clip{Name: name, Frames: frames, Loop: true}
+	switch len(durations) {
+	case 0:
+		for _, i := range frames {
+			d := s.Frames[i].Duration
+			if d <= 0 {
+				d = DefaultFrameDuration
+			}
+
+			c.Durations = append(c.Durations, d)
+		}
+	case 1:
+		for range frames {
+			c.Durations = append(c.Durations, durations[0])
+		}
+	case len(frames):
+		c.Durations = durations
+	default:
+		return nil, fmt.Errorf("sprite: %d durations given for %d frames in clip %q", len(durations), len(frames), name)
+	}
+
+	s.Clips[name] = c
+	return c, nil
+}
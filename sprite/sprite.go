@@ -0,0 +1,106 @@
+package sprite
+
+import (
+	"image"
+	"image/draw"
+	"time"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+)
+
+// Sprite is a movable image, animated with the clips of a sheet
+type Sprite struct {
+	// Sheet containing the frames of the sprite
+	Sheet *Sheet
+	// Position of the top left corner of the sprite
+	Position image.Point
+	// Hidden sprites are not drawn
+	Hidden bool
+	// Clock used to play the clips, SystemClock if nil, the Scene sets its
+	// Clock to its sprites
+	Clock rgbmatrix.Clock
+
+	frame int
+	clip  *Clip
+	start time.Time
+}
+
+// NewSprite returns a new Sprite showing the first frame of the sheet
+func NewSprite(s *Sheet) *Sprite {
+	return &Sprite{Sheet: s}
+}
+
+// Play starts the clip with the given name from the beginning, returns false
+// if the sheet doesn't have the clip
+func (s *Sprite) Play(name string) bool {
+	c, ok := s.Sheet.Clips[name]
+	if !ok {
+		return false
+	}
+
+	s.clip = c
+	s.start = s.clock().Now()
+	return true
+}
+
+// SetFrame stops the current clip and shows the frame i of the sheet
+func (s *Sprite) SetFrame(i int) {
+	s.clip = nil
+	s.frame = i
+}
+
+// Done returns true if the current clip ended, or no clip is being played
+func (s *Sprite) Done() bool {
+	if s.clip == nil {
+		return true
+	}
+
+	_, done := s.clip.At(s.clock().Now().Sub(s.start))
+	return done
+}
+
+// MoveTo moves the sprite to the given position
+func (s *Sprite) MoveTo(p image.Point) {
+	s.Position = p
+}
+
+// Move moves the sprite by the given offset
+func (s *Sprite) Move(dx, dy int) {
+	s.Position = s.Position.Add(image.Pt(dx, dy))
+}
+
+// Frame returns the index of the frame of the sheet shown
+func (s *Sprite) Frame() int {
+	if s.clip == nil {
+		return s.frame
+	}
+
+	i, _ := s.clip.At(s.clock().Now().Sub(s.start))
+	return i
+}
+
+func (s *Sprite) clock() rgbmatrix.Clock {
+	return rgbmatrix.ClockOrSystem(s.Clock)
+}
+
+// Bounds returns the area covered by the sprite
+func (s *Sprite) Bounds() image.Rectangle {
+	i := s.Frame()
+	if i < 0 || i >= len(s.Sheet.Frames) {
+		return image.Rectangle{}
+	}
+
+	size := s.Sheet.Frames[i].Bounds.Size()
+	return image.Rectangle{s.Position, s.Position.Add(size)}
+}
+
+// Draw draws the current frame of the sprite over dst, the transparent pixels
+// of the frame are not drawn
+func (s *Sprite) Draw(dst draw.Image) {
+	if s.Hidden {
+		return
+	}
+
+	img := s.Sheet.Image(s.Frame())
+	draw.Draw(dst, s.Bounds(), img, img.Bounds().Min, draw.Over)
+}
@@ -0,0 +1,162 @@
+package sprite
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type SpriteSuite struct{}
+
+var _ = Suite(&SpriteSuite{})
+
+var (
+	red   = color.RGBA{255, 0, 0, 255}
+	green = color.RGBA{0, 255, 0, 255}
+	blue  = color.RGBA{0, 0, 255, 255}
+	black = color.RGBA{0, 0, 0, 255}
+)
+
+// newTestSheet returns a 3x1 grid of 2x2 frames, red, green and blue, with
+// a transparent pixel at the bottom right corner of every frame
+func newTestSheet() *Sheet {
+	img := image.NewRGBA(image.Rect(0, 0, 6, 2))
+	for i, c := range []color.RGBA{red, green, blue} {
+		img.Set(i*2, 0, c)
+		img.Set(i*2+1, 0, c)
+		img.Set(i*2, 1, c)
+	}
+
+	return NewGridSheet(img, image.Pt(2, 2))
+}
+
+func (s *SpriteSuite) TestGridSheet(c *C) {
+	sh := newTestSheet()
+	c.Assert(sh.Frames, HasLen, 3)
+	c.Assert(sh.Frames[1].Bounds, Equals, image.Rect(2, 0, 4, 2))
+	c.Assert(sh.Image(2).At(4, 0), Equals, blue)
+}
+
+func (s *SpriteSuite) TestClipAt(c *C) {
+	clip := &Clip{
+		Frames:    []int{0, 1, 2},
+		Durations: []time.Duration{10, 20, 30},
+	}
+
+	i, done := clip.At(15)
+	c.Assert(i, Equals, 1)
+	c.Assert(done, Equals, false)
+
+	i, done = clip.At(60)
+	c.Assert(i, Equals, 2)
+	c.Assert(done, Equals, true)
+
+	clip.Loop = true
+	i, done = clip.At(65)
+	c.Assert(i, Equals, 0)
+	c.Assert(done, Equals, false)
+}
+
+func (s *SpriteSuite) TestDefine(c *C) {
+	sh := newTestSheet()
+	clip, err := sh.Define("walk", []int{0, 1}, time.Second)
+	c.Assert(err, IsNil)
+	c.Assert(clip.Durations, DeepEquals, []time.Duration{time.Second, time.Second})
+
+	_, err = sh.Define("bad", []int{0, 3})
+	c.Assert(err, NotNil)
+
+	_, err = sh.Define("bad", []int{0, 1, 2}, 1, 2)
+	c.Assert(err, NotNil)
+}
+
+func (s *SpriteSuite) TestAtlasSheet(c *C) {
+	img := image.NewRGBA(image.Rect(0, 0, 6, 2))
+	sh, err := NewAtlasSheet(img, strings.NewReader(`{
+		"frames": {
+			"b": {"frame": {"x": 2, "y": 0, "w": 2, "h": 2}, "duration": 50},
+			"a": {"frame": {"x": 0, "y": 0, "w": 2, "h": 2}, "duration": 100},
+			"c": {"frame": {"x": 4, "y": 0, "w": 2, "h": 2}}
+		},
+		"meta": {"frameTags": [{"name": "bounce", "from": 0, "to": 2, "direction": "pingpong"}]}
+	}`))
+	c.Assert(err, IsNil)
+	c.Assert(sh.Frames, HasLen, 3)
+	c.Assert(sh.Frames[0].Name, Equals, "b")
+	c.Assert(sh.Frames[1].Duration, Equals, 100*time.Millisecond)
+
+	i, ok := sh.Lookup("c")
+	c.Assert(ok, Equals, true)
+	c.Assert(i, Equals, 2)
+
+	clip := sh.Clips["bounce"]
+	c.Assert(clip, NotNil)
+	c.Assert(clip.Frames, DeepEquals, []int{0, 1, 2, 1})
+	c.Assert(clip.Durations[2], Equals, DefaultFrameDuration)
+
+	sh, err = NewAtlasSheet(img, strings.NewReader(`{"frames": [
+		{"filename": "x", "frame": {"x": 0, "y": 0, "w": 6, "h": 2}}
+	]}`))
+	c.Assert(err, IsNil)
+	c.Assert(sh.Frames, HasLen, 1)
+	c.Assert(sh.Frames[0].Bounds, Equals, image.Rect(0, 0, 6, 2))
+}
+
+func (s *SpriteSuite) TestSpriteDraw(c *C) {
+	dst := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	dst.Set(2, 2, black)
+	dst.Set(3, 3, blue)
+
+	sp := NewSprite(newTestSheet())
+	sp.SetFrame(1)
+	sp.MoveTo(image.Pt(1, 1))
+	sp.Move(1, 1)
+	sp.Draw(dst)
+
+	c.Assert(sp.Bounds(), Equals, image.Rect(2, 2, 4, 4))
+	c.Assert(dst.At(2, 2), Equals, green)
+	c.Assert(dst.At(3, 3), Equals, blue)
+}
+
+func (s *SpriteSuite) TestTileMap(c *C) {
+	m := NewTileMap(newTestSheet(), image.Pt(2, 2), 2, 1)
+	m.Set(0, 0, 0)
+	m.Set(1, 0, 2)
+	m.Offset = image.Pt(1, 0)
+
+	dst := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	m.Draw(dst)
+	c.Assert(dst.At(0, 0), Equals, red)
+	c.Assert(dst.At(1, 0), Equals, blue)
+	c.Assert(dst.At(3, 0), Equals, color.RGBA{})
+
+	m.Wrap = true
+	m.Draw(dst)
+	c.Assert(dst.At(3, 0), Equals, red)
+	c.Assert(m.At(-1, 0), Equals, 2)
+}
+
+func (s *SpriteSuite) TestScene(c *C) {
+	sc := NewScene(image.Pt(4, 4))
+	sp := NewSprite(newTestSheet())
+	sc.Add(sp)
+
+	var updates int
+	sc.Update = func(s *Scene, elapsed time.Duration) error {
+		updates++
+		s.Sprites[0].Move(1, 0)
+		return nil
+	}
+
+	img, _, err := sc.Next()
+	c.Assert(err, IsNil)
+	c.Assert(updates, Equals, 1)
+	c.Assert(img.At(0, 0), Equals, black)
+	c.Assert(img.At(1, 0), Equals, red)
+}
@@ -0,0 +1,119 @@
+package sprite
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Empty is the tile of the cells without a tile
+const Empty = -1
+
+// TileMap is a grid of tiles, the frames of a sheet, that can be bigger than
+// the matrix and scrolled
+type TileMap struct {
+	// Sheet containing the tiles
+	Sheet *Sheet
+	// TileSize is the size of every cell of the map
+	TileSize image.Point
+	// Columns and Rows of the map
+	Columns, Rows int
+	// Tiles are the frame indexes of every cell, row by row
+	Tiles []int
+	// Offset is the position in the map of the top left corner of the
+	// viewport
+	Offset image.Point
+	// Wrap repeats the map in every direction when the viewport is out of it
+	Wrap bool
+}
+
+// NewTileMap returns a new empty TileMap with the given tile size and number
+// of columns and rows
+func NewTileMap(s *Sheet, tileSize image.Point, columns, rows int) *TileMap {
+	m := &TileMap{
+		Sheet:    s,
+		TileSize: tileSize,
+		Columns:  columns,
+		Rows:     rows,
+		Tiles:    make([]int, columns*rows),
+	}
+
+	for i := range m.Tiles {
+		m.Tiles[i] = Empty
+	}
+
+	return m
+}
+
+// Set sets the tile of the cell at column x and row y
+func (m *TileMap) Set(x, y, tile int) {
+	if x < 0 || y < 0 || x >= m.Columns || y >= m.Rows {
+		return
+	}
+
+	m.Tiles[y*m.Columns+x] = tile
+}
+
+// At returns the tile of the cell at column x and row y, Empty if the cell is
+// out of the map, unless Wrap is enabled
+func (m *TileMap) At(x, y int) int {
+	if m.Wrap && m.Columns > 0 && m.Rows > 0 {
+		x, y = mod(x, m.Columns), mod(y, m.Rows)
+	}
+
+	if x < 0 || y < 0 || x >= m.Columns || y >= m.Rows {
+		return Empty
+	}
+
+	return m.Tiles[y*m.Columns+x]
+}
+
+// Scroll moves the viewport by the given offset
+func (m *TileMap) Scroll(dx, dy int) {
+	m.Offset = m.Offset.Add(image.Pt(dx, dy))
+}
+
+// Size returns the size of the map in pixels
+func (m *TileMap) Size() image.Point {
+	return image.Pt(m.Columns*m.TileSize.X, m.Rows*m.TileSize.Y)
+}
+
+// Draw draws the tiles visible in the viewport over dst
+func (m *TileMap) Draw(dst draw.Image) {
+	if m.TileSize.X <= 0 || m.TileSize.Y <= 0 {
+		return
+	}
+
+	b := dst.Bounds()
+	first := image.Pt(floorDiv(m.Offset.X, m.TileSize.X), floorDiv(m.Offset.Y, m.TileSize.Y))
+	for y := first.Y; y*m.TileSize.Y-m.Offset.Y < b.Dy(); y++ {
+		for x := first.X; x*m.TileSize.X-m.Offset.X < b.Dx(); x++ {
+			tile := m.At(x, y)
+			if tile == Empty {
+				continue
+			}
+
+			img := m.Sheet.Image(tile)
+			p := b.Min.Add(image.Pt(x*m.TileSize.X, y*m.TileSize.Y)).Sub(m.Offset)
+			r := image.Rectangle{p, p.Add(m.TileSize)}
+			draw.Draw(dst, r, img, img.Bounds().Min, draw.Over)
+		}
+	}
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+
+	return q
+}
+
+func mod(a, b int) int {
+	m := a % b
+	if m < 0 {
+		m += b
+	}
+
+	return m
+}
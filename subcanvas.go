@@ -0,0 +1,202 @@
+package rgbmatrix
+
+import (
+	"image"
+	"image/color"
+	"sync"
+	"time"
+)
+
+// subCanvasFrameInterval is the time waited, since the first SubCanvas is
+// rendered, to render the parent Canvas, so the Render calls of all the
+// SubCanvas during a frame end in a single Render of the Canvas
+const subCanvasFrameInterval = time.Second / 60
+
+// SubCanvas is a viewport over a rectangle of a Canvas, it is a Canvas with
+// its own Bounds starting at 0,0. Every SubCanvas keeps its own LED buffer, so
+// independent ToolKits or Animations can drive each region concurrently, the
+// Render calls are coordinated, rendering the parent Canvas once per frame.
+// Since the parent Canvas is rendered later, an error rendering it is returned
+// by the next Render call of any SubCanvas, and by Err.
+//
+// A ToolKit can be used with a SubCanvas using directly the struct:
+//
+//	tk := &rgbmatrix.ToolKit{Canvas: sub.Canvas}
+type SubCanvas struct {
+	*Canvas
+	// Rect is the area of the parent Canvas covered by the SubCanvas
+	Rect image.Rectangle
+	// Clock used to wait for the Render calls of the other SubCanvas, before
+	// rendering the parent Canvas, SystemClock if nil
+	Clock Clock
+
+	z *zones
+}
+
+// NewSubCanvas returns a new SubCanvas covering the given rectangle of the
+// parent Canvas, the rectangle is clipped to the bounds of the parent. The
+// SubCanvas created later are drawn on top of the previous ones.
+func NewSubCanvas(parent *Canvas, r image.Rectangle) *SubCanvas {
+	r = r.Intersect(parent.Bounds())
+	s := &SubCanvas{Rect: r, z: parent.getZones()}
+	m := &regionMatrix{
+		z:    s.z,
+		s:    s,
+		r:    r,
+		leds: make([]color.Color, r.Dx()*r.Dy()),
+	}
+
+	m.z.add(m)
+	s.Canvas = NewCanvas(m)
+	return s
+}
+
+// Err returns the error of the last render of the parent Canvas, if any
+func (s *SubCanvas) Err() error {
+	s.z.m.Lock()
+	defer s.z.m.Unlock()
+
+	return s.z.last
+}
+
+func (c *Canvas) getZones() *zones {
+	c.zm.Lock()
+	defer c.zm.Unlock()
+
+	if c.zones == nil {
+		c.zones = &zones{c: c}
+	}
+
+	return c.zones
+}
+
+// zones coordinates the rendering of all the SubCanvas of a Canvas
+type zones struct {
+	c *Canvas
+
+	m       sync.Mutex
+	regions []*regionMatrix
+	pending bool
+	// err is the error of the last render, until returned by render
+	err error
+	// last is the error of the last render, nil if it succeeded
+	last error
+}
+
+func (z *zones) add(r *regionMatrix) {
+	z.m.Lock()
+	defer z.m.Unlock()
+
+	z.regions = append(z.regions, r)
+}
+
+func (z *zones) remove(r *regionMatrix) {
+	z.m.Lock()
+	defer z.m.Unlock()
+
+	for i, region := range z.regions {
+		if region == r {
+			z.regions = append(z.regions[:i], z.regions[i+1:]...)
+			break
+		}
+	}
+}
+
+// render schedules a render of the parent Canvas using the given clock, if none
+// is pending, and returns the error of the previous render, if any
+func (z *zones) render(clock Clock) error {
+	z.m.Lock()
+	defer z.m.Unlock()
+
+	err := z.err
+	z.err = nil
+
+	if !z.pending {
+		z.pending = true
+		clock.AfterFunc(subCanvasFrameInterval, z.flush)
+	}
+
+	return err
+}
+
+// flush copies the LED buffer of every region to the parent Canvas and
+// renders it
+func (z *zones) flush() {
+	z.m.Lock()
+	defer z.m.Unlock()
+
+	z.pending = false
+	for _, r := range z.regions {
+		r.copyTo(z.c)
+	}
+
+	z.last = z.c.Render()
+	if z.last != nil {
+		z.err = z.last
+	}
+}
+
+// regionMatrix is the Matrix behind a SubCanvas
+type regionMatrix struct {
+	z *zones
+	s *SubCanvas
+	r image.Rectangle
+
+	m    sync.Mutex
+	leds []color.Color
+}
+
+func (m *regionMatrix) Geometry() (width, height int) {
+	return m.r.Dx(), m.r.Dy()
+}
+
+func (m *regionMatrix) At(position int) color.Color {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	if m.leds[position] == nil {
+		return color.Black
+	}
+
+	return m.leds[position]
+}
+
+func (m *regionMatrix) Set(position int, c color.Color) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	m.leds[position] = color.RGBAModel.Convert(c)
+}
+
+func (m *regionMatrix) Apply(leds []color.Color) error {
+	for position, l := range leds {
+		m.Set(position, l)
+	}
+
+	return m.Render()
+}
+
+// Render schedules a render of the parent Canvas, the LED buffer is kept
+func (m *regionMatrix) Render() error {
+	return m.z.render(ClockOrSystem(m.s.Clock))
+}
+
+// Close detaches the region from the parent Canvas
+func (m *regionMatrix) Close() error {
+	m.z.remove(m)
+	return nil
+}
+
+func (m *regionMatrix) copyTo(c *Canvas) {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	w := m.r.Dx()
+	for i, l := range m.leds {
+		if l == nil {
+			l = color.Black
+		}
+
+		c.Set(m.r.Min.X+i%w, m.r.Min.Y+i/w, l)
+	}
+}
@@ -0,0 +1,73 @@
+package rgbmatrix
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type SubCanvasSuite struct{}
+
+var _ = Suite(&SubCanvasSuite{})
+
+func (s *SubCanvasSuite) TestBounds(c *C) {
+	canvas := &Canvas{w: 10, h: 20, m: NewMatrixMock()}
+	sub := NewSubCanvas(canvas, image.Rect(5, 15, 20, 30))
+
+	c.Assert(sub.Rect, Equals, image.Rect(5, 15, 10, 20))
+	c.Assert(sub.Bounds(), Equals, image.Rect(0, 0, 5, 5))
+	c.Assert(sub.At(1, 1), Equals, color.Black)
+}
+
+func (s *SubCanvasSuite) TestRender(c *C) {
+	clock := NewFakeClock(epoch)
+	m := NewMatrixMock()
+	canvas := &Canvas{w: 10, h: 20, m: m}
+
+	a := NewSubCanvas(canvas, image.Rect(0, 0, 5, 20))
+	b := NewSubCanvas(canvas, image.Rect(5, 0, 10, 20))
+	a.Clock, b.Clock = clock, clock
+
+	a.Set(1, 1, red)
+	b.Set(1, 1, blue)
+	c.Assert(a.Render(), IsNil)
+	c.Assert(b.Render(), IsNil)
+	c.Assert(clock.Waiters(), Equals, 1)
+
+	clock.Advance(subCanvasFrameInterval - time.Nanosecond)
+	c.Assert(m.called["Render"], IsNil)
+
+	clock.Advance(time.Nanosecond)
+	c.Assert(m.called["Render"], Equals, true)
+	c.Assert(m.colors[11], Equals, red)
+	c.Assert(m.colors[16], Equals, blue)
+	c.Assert(m.colors[12], Equals, color.Black)
+}
+
+// failingMatrix is a MatrixMock failing every Render
+type failingMatrix struct {
+	*MatrixMock
+}
+
+func (m failingMatrix) Render() error {
+	return errors.New("render failed")
+}
+
+func (s *SubCanvasSuite) TestErr(c *C) {
+	clock := NewFakeClock(epoch)
+	canvas := &Canvas{w: 10, h: 20, m: failingMatrix{NewMatrixMock()}}
+
+	sub := NewSubCanvas(canvas, image.Rect(0, 0, 5, 20))
+	sub.Clock = clock
+
+	c.Assert(sub.Render(), IsNil)
+	c.Assert(sub.Err(), IsNil)
+
+	clock.Advance(subCanvasFrameInterval)
+	c.Assert(sub.Err(), ErrorMatches, "render failed")
+	c.Assert(sub.Render(), ErrorMatches, "render failed")
+	c.Assert(sub.Err(), ErrorMatches, "render failed")
+}
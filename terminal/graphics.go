@@ -0,0 +1,133 @@
+package terminal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+)
+
+// sixelLevels is the number of levels per channel of the sixel palette, 6
+// levels give a palette of 216 colors, within the 256 registers supported by
+// most terminals
+const sixelLevels = 6
+
+// writeSixel draws the leds as a sixel image of the given size
+func (t *Terminal) writeSixel(w *bytes.Buffer, size image.Point) {
+	img := t.image(size)
+
+	// palette indexes of every pixel, and the registers used
+	pixels := make([]int, size.X*size.Y)
+	used := make([]bool, sixelLevels*sixelLevels*sixelLevels)
+	for i := range pixels {
+		p := img.Pix[i*4 : i*4+3]
+		pixels[i] = (level(p[0])*sixelLevels+level(p[1]))*sixelLevels + level(p[2])
+		used[pixels[i]] = true
+	}
+
+	fmt.Fprintf(w, "\x1bPq\"1;1;%d;%d", size.X, size.Y)
+	for i, ok := range used {
+		if !ok {
+			continue
+		}
+
+		r, g, b := i/(sixelLevels*sixelLevels), i/sixelLevels%sixelLevels, i%sixelLevels
+		fmt.Fprintf(w, "#%d;2;%d;%d;%d", i, r*100/(sixelLevels-1), g*100/(sixelLevels-1), b*100/(sixelLevels-1))
+	}
+
+	// every band is six pixels high, drawn once per color in it
+	band := make([]byte, size.X)
+	for y := 0; y < size.Y; y += 6 {
+		colors := make(map[int]bool)
+		for i := y * size.X; i < (y+6)*size.X && i < len(pixels); i++ {
+			colors[pixels[i]] = true
+		}
+
+		first := true
+		for c := range used {
+			if !colors[c] {
+				continue
+			}
+
+			for x := range band {
+				band[x] = 0
+				for dy := 0; dy < 6 && y+dy < size.Y; dy++ {
+					if pixels[(y+dy)*size.X+x] == c {
+						band[x] |= 1 << uint(dy)
+					}
+				}
+			}
+
+			if !first {
+				w.WriteByte('$')
+			}
+
+			first = false
+			fmt.Fprintf(w, "#%d", c)
+			writeSixelRLE(w, band)
+		}
+
+		w.WriteByte('-')
+	}
+
+	w.WriteString("\x1b\\")
+}
+
+// writeSixelRLE writes the sixels of a band, compressing the repetitions
+func writeSixelRLE(w *bytes.Buffer, band []byte) {
+	for x := 0; x < len(band); {
+		n := 1
+		for x+n < len(band) && band[x+n] == band[x] {
+			n++
+		}
+
+		c := band[x] + '?'
+		if n > 3 {
+			fmt.Fprintf(w, "!%d%c", n, c)
+		} else {
+			w.Write(bytes.Repeat([]byte{c}, n))
+		}
+
+		x += n
+	}
+}
+
+// level returns the nearest sixel palette level of v
+func level(v uint8) int {
+	return (int(v)*(sixelLevels-1) + 127) / 255
+}
+
+// kittyImageID is the id of the image transmitted, reused by every frame so
+// the new frames replace the previous ones
+const kittyImageID = 1
+
+// kittyChunkSize is the max size of the payload of every escape sequence
+const kittyChunkSize = 4096
+
+// writeKitty draws the leds as an image of the given size, using the kitty
+// graphics protocol
+func (t *Terminal) writeKitty(w *bytes.Buffer, size image.Point) {
+	img := t.image(size)
+
+	rgb := make([]byte, 0, size.X*size.Y*3)
+	for i := 0; i < len(img.Pix); i += 4 {
+		rgb = append(rgb, img.Pix[i:i+3]...)
+	}
+
+	data := base64.StdEncoding.EncodeToString(rgb)
+	for i := 0; i < len(data); i += kittyChunkSize {
+		end := i + kittyChunkSize
+		more := 1
+		if end >= len(data) {
+			end, more = len(data), 0
+		}
+
+		if i == 0 {
+			fmt.Fprintf(w, "\x1b_Ga=T,f=24,s=%d,v=%d,i=%d,q=2,C=1,m=%d;%s\x1b\\",
+				size.X, size.Y, kittyImageID, more, data[i:end])
+			continue
+		}
+
+		fmt.Fprintf(w, "\x1b_Gm=%d;%s\x1b\\", more, data[i:end])
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package terminal
+
+import "os"
+
+// terminalSize returns zero, the size of the terminal is unknown in this
+// platform and the default size is used
+func terminalSize(f *os.File) (cols, rows, width, height int) {
+	return 0, 0, 0, 0
+}
@@ -0,0 +1,19 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package terminal
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminalSize returns the size of the terminal f, zero if f is not a terminal
+func terminalSize(f *os.File) (cols, rows, width, height int) {
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, 0, 0
+	}
+
+	return int(ws.Col), int(ws.Row), int(ws.Xpixel), int(ws.Ypixel)
+}
@@ -0,0 +1,305 @@
+// Package terminal implements a Matrix rendering into a terminal, useful to
+// see the content of a matrix through SSH. The frames are drawn using ANSI
+// 24-bit colors and Unicode half blocks, two LEDs per character, or as images
+// with the sixel or kitty graphics protocols when the terminal supports them.
+package terminal
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Mode is the way the frames are drawn in the terminal
+type Mode int
+
+const (
+	// Auto detects the best Mode supported by the terminal
+	Auto Mode = iota
+	// HalfBlocks draws two LEDs per character, using the upper half block with
+	// the foreground and background 24-bit colors
+	HalfBlocks
+	// Sixel draws the frames as sixel images
+	Sixel
+	// Kitty draws the frames using the kitty graphics protocol
+	Kitty
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Auto:
+		return "auto"
+	case HalfBlocks:
+		return "halfblocks"
+	case Sixel:
+		return "sixel"
+	case Kitty:
+		return "kitty"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+}
+
+// ModeENV is the environment variable that overrides the Mode detected, with
+// one of "halfblocks", "sixel" or "kitty"
+const ModeENV = "MATRIX_TERMINAL_MODE"
+
+// DetectMode returns the Mode supported by the terminal, based on the
+// environment variables, since querying the terminal would consume the input
+// of the program
+func DetectMode() Mode {
+	switch strings.ToLower(os.Getenv(ModeENV)) {
+	case "halfblocks":
+		return HalfBlocks
+	case "sixel":
+		return Sixel
+	case "kitty":
+		return Kitty
+	}
+
+	term, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty",
+		program == "WezTerm", program == "ghostty":
+		return Kitty
+	case strings.Contains(term, "sixel"), strings.HasPrefix(term, "mlterm"),
+		strings.HasPrefix(term, "foot"), program == "iTerm.app", program == "mintty":
+		return Sixel
+	default:
+		return HalfBlocks
+	}
+}
+
+// Terminal is a Matrix drawing the frames in a terminal, scaled to fit on it,
+// every frame replaces the previous one in the same place
+type Terminal struct {
+	Width  int
+	Height int
+	// Mode used to draw the frames, detected with DetectMode if Auto
+	Mode Mode
+	// Output where the frames are written, os.Stdout if nil
+	Output io.Writer
+	// Size returns the size of the terminal, in characters and in pixels, if
+	// nil the size of the terminal of Output is used, or 80x24 characters if
+	// is not a terminal. The pixels may be zero if unknown.
+	Size func() (cols, rows, width, height int)
+
+	m       sync.Mutex
+	leds    []color.Color
+	buf     bytes.Buffer
+	started bool
+}
+
+// NewTerminal returns a new Terminal of w x h leds
+func NewTerminal(w, h int) *Terminal {
+	return &Terminal{
+		Width:  w,
+		Height: h,
+		leds:   make([]color.Color, w*h),
+	}
+}
+
+func (t *Terminal) Geometry() (width, height int) {
+	return t.Width, t.Height
+}
+
+// Apply set all the pixels to the values contained in leds and renders them
+func (t *Terminal) Apply(leds []color.Color) error {
+	for position, l := range leds {
+		t.Set(position, l)
+	}
+
+	return t.Render()
+}
+
+// Render draws the current leds in the terminal, and sets all of them to
+// black, as the hardware matrix does
+func (t *Terminal) Render() error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	defer func() { t.leds = make([]color.Color, t.Width*t.Height) }()
+
+	t.buf.Reset()
+	if !t.started {
+		// hides the cursor and clears the screen
+		t.buf.WriteString("\x1b[?25l\x1b[2J")
+		t.started = true
+	}
+
+	t.buf.WriteString("\x1b[H")
+	cols, rows, width, height := t.size()
+	switch t.mode() {
+	case Sixel:
+		t.writeSixel(&t.buf, t.scale(width, height, cols, rows))
+	case Kitty:
+		t.writeKitty(&t.buf, t.scale(width, height, cols, rows))
+	default:
+		t.writeHalfBlocks(&t.buf, cols, rows)
+		// clears what is left of a larger previous frame
+		t.buf.WriteString("\x1b[J")
+	}
+
+	_, err := t.output().Write(t.buf.Bytes())
+	return err
+}
+
+func (t *Terminal) At(position int) color.Color {
+	if t.leds[position] == nil {
+		return color.Black
+	}
+
+	return t.leds[position]
+}
+
+func (t *Terminal) Set(position int, c color.Color) {
+	t.leds[position] = color.RGBAModel.Convert(c)
+}
+
+// Close restores the cursor and the colors of the terminal
+func (t *Terminal) Close() error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if !t.started {
+		return nil
+	}
+
+	t.started = false
+	_, err := io.WriteString(t.output(), "\x1b[0m\x1b[?25h\r\n")
+	return err
+}
+
+// writeHalfBlocks draws the leds scaled to fit in cols x rows characters,
+// keeping the last row free to avoid scrolling the terminal
+func (t *Terminal) writeHalfBlocks(w *bytes.Buffer, cols, rows int) {
+	size := fit(t.Width, t.Height, cols, 2*(rows-1))
+
+	var fg, bg color.RGBA
+	for y := 0; y < size.Y; y += 2 {
+		for x := 0; x < size.X; x++ {
+			top := t.sample(x, y, size)
+			bottom := top
+			if y+1 < size.Y {
+				bottom = t.sample(x, y+1, size)
+			}
+
+			if x == 0 || top != fg {
+				fmt.Fprintf(w, "\x1b[38;2;%d;%d;%dm", top.R, top.G, top.B)
+				fg = top
+			}
+
+			if x == 0 || bottom != bg {
+				fmt.Fprintf(w, "\x1b[48;2;%d;%d;%dm", bottom.R, bottom.G, bottom.B)
+				bg = bottom
+			}
+
+			w.WriteString("▀")
+		}
+
+		w.WriteString("\x1b[0m\x1b[K")
+		if y+2 < size.Y {
+			w.WriteString("\r\n")
+		}
+	}
+}
+
+// scale returns the size in pixels of the images drawn by the graphic modes,
+// the terminal pixels are estimated from the characters if unknown
+func (t *Terminal) scale(width, height, cols, rows int) image.Point {
+	if width <= 0 || height <= 0 {
+		width, height = cols*8, rows*16
+	}
+
+	// keeps a row free to avoid scrolling the terminal
+	return fit(t.Width, t.Height, width, height-height/rows)
+}
+
+// sample returns the color of the led shown at the pixel (x, y) of a frame
+// scaled to the given size
+func (t *Terminal) sample(x, y int, size image.Point) color.RGBA {
+	col := x * t.Width / size.X
+	row := y * t.Height / size.Y
+	return color.RGBAModel.Convert(t.At(col + row*t.Width)).(color.RGBA)
+}
+
+// image returns the leds as an image scaled to the given size
+func (t *Terminal) image(size image.Point) *image.RGBA {
+	img := image.NewRGBA(image.Rectangle{Max: size})
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			img.SetRGBA(x, y, t.sample(x, y, size))
+		}
+	}
+
+	return img
+}
+
+func (t *Terminal) mode() Mode {
+	if t.Mode == Auto {
+		t.Mode = DetectMode()
+	}
+
+	return t.Mode
+}
+
+func (t *Terminal) output() io.Writer {
+	if t.Output == nil {
+		return os.Stdout
+	}
+
+	return t.Output
+}
+
+func (t *Terminal) size() (cols, rows, width, height int) {
+	if t.Size != nil {
+		cols, rows, width, height = t.Size()
+	} else if f, ok := t.output().(*os.File); ok {
+		cols, rows, width, height = terminalSize(f)
+	}
+
+	if cols <= 0 || rows <= 1 {
+		cols, rows, width, height = 80, 24, 0, 0
+	}
+
+	return
+}
+
+// fit returns the largest size with the proportions of w x h fitting in
+// maxW x maxH, integer multiples of w x h are used when possible to keep all
+// the leds of the same size
+func fit(w, h, maxW, maxH int) image.Point {
+	if maxW < 1 || maxH < 1 {
+		return image.Pt(w, h)
+	}
+
+	s := maxW / w
+	if maxH/h < s {
+		s = maxH / h
+	}
+
+	if s >= 1 {
+		return image.Pt(w*s, h*s)
+	}
+
+	// the matrix is larger than the terminal, some leds are not shown
+	size := image.Pt(w*maxH/h, maxH)
+	if maxW*h < maxH*w {
+		size = image.Pt(maxW, h*maxW/w)
+	}
+
+	if size.X < 1 {
+		size.X = 1
+	}
+
+	if size.Y < 1 {
+		size.Y = 1
+	}
+
+	return size
+}
@@ -0,0 +1,118 @@
+package terminal
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"os"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type TerminalSuite struct{}
+
+var _ = Suite(&TerminalSuite{})
+
+func newTestTerminal(mode Mode, cols, rows int) (*Terminal, *bytes.Buffer) {
+	buf := bytes.NewBuffer(nil)
+	t := NewTerminal(2, 2)
+	t.Mode = mode
+	t.Output = buf
+	t.Size = func() (int, int, int, int) { return cols, rows, 0, 0 }
+	return t, buf
+}
+
+func (s *TerminalSuite) TestHalfBlocks(c *C) {
+	t, buf := newTestTerminal(HalfBlocks, 2, 2)
+	t.Set(0, color.RGBA{255, 0, 0, 255})
+	t.Set(3, color.RGBA{0, 0, 255, 255})
+	c.Assert(t.Render(), IsNil)
+
+	c.Assert(buf.String(), Equals, "\x1b[?25l\x1b[2J\x1b[H"+
+		"\x1b[38;2;255;0;0m\x1b[48;2;0;0;0m▀"+
+		"\x1b[38;2;0;0;0m\x1b[48;2;0;0;255m▀"+
+		"\x1b[0m\x1b[K\x1b[J",
+	)
+
+	buf.Reset()
+	c.Assert(t.Render(), IsNil)
+	c.Assert(strings.HasPrefix(buf.String(), "\x1b[H"), Equals, true)
+
+	buf.Reset()
+	c.Assert(t.Close(), IsNil)
+	c.Assert(buf.String(), Equals, "\x1b[0m\x1b[?25h\r\n")
+}
+
+func (s *TerminalSuite) TestHalfBlocksScaled(c *C) {
+	t, buf := newTestTerminal(HalfBlocks, 80, 24)
+	c.Assert(t.Render(), IsNil)
+
+	// 2x2 leds scaled by 23 to fit in 23 rows of two pixels
+	c.Assert(strings.Count(buf.String(), "▀"), Equals, 46*23)
+	c.Assert(strings.Count(buf.String(), "\r\n"), Equals, 22)
+}
+
+func (s *TerminalSuite) TestSixel(c *C) {
+	t, buf := newTestTerminal(Sixel, 1, 2)
+	t.Set(0, color.RGBA{255, 0, 0, 255})
+	c.Assert(t.Render(), IsNil)
+
+	// 1 row of 16 pixels is kept free, the image is scaled to 8x8
+	c.Assert(buf.String(), Equals, "\x1b[?25l\x1b[2J\x1b[H"+
+		"\x1bPq\"1;1;8;8#0;2;0;0;0#180;2;100;0;0"+
+		"#0!4o!4~$#180!4N!4?-"+
+		"#0!8B-"+
+		"\x1b\\",
+	)
+}
+
+func (s *TerminalSuite) TestKitty(c *C) {
+	t, buf := newTestTerminal(Kitty, 1, 2)
+	c.Assert(t.Render(), IsNil)
+
+	out := buf.String()
+	c.Assert(strings.HasPrefix(out, "\x1b[?25l\x1b[2J\x1b[H\x1b_Ga=T,f=24,s=8,v=8,i=1,q=2,C=1,m=0;AAAA"), Equals, true)
+	c.Assert(strings.HasSuffix(out, "\x1b\\"), Equals, true)
+}
+
+func (s *TerminalSuite) TestKittyChunks(c *C) {
+	t, buf := newTestTerminal(Kitty, 80, 24)
+	t.Size = func() (int, int, int, int) { return 80, 24, 800, 480 }
+	c.Assert(t.Render(), IsNil)
+
+	// 460x460 pixels of 3 bytes, in base64 chunks of 4096
+	c.Assert(strings.Count(buf.String(), "\x1b_G"), Equals, (460*460*3/3*4+4095)/4096)
+}
+
+func (s *TerminalSuite) TestFit(c *C) {
+	c.Assert(fit(64, 32, 80, 44), Equals, image.Pt(64, 32))
+	c.Assert(fit(64, 32, 200, 100), Equals, image.Pt(192, 96))
+	c.Assert(fit(64, 32, 40, 100), Equals, image.Pt(40, 20))
+	c.Assert(fit(64, 32, 0, 0), Equals, image.Pt(64, 32))
+}
+
+func (s *TerminalSuite) TestDetectMode(c *C) {
+	defer os.Setenv(ModeENV, os.Getenv(ModeENV))
+	defer os.Setenv("TERM", os.Getenv("TERM"))
+	defer os.Setenv("TERM_PROGRAM", os.Getenv("TERM_PROGRAM"))
+	defer os.Setenv("KITTY_WINDOW_ID", os.Getenv("KITTY_WINDOW_ID"))
+	os.Setenv("TERM_PROGRAM", "")
+	os.Setenv("KITTY_WINDOW_ID", "")
+
+	os.Setenv(ModeENV, "")
+	os.Setenv("TERM", "xterm-256color")
+	c.Assert(DetectMode(), Equals, HalfBlocks)
+
+	os.Setenv("TERM", "xterm-kitty")
+	c.Assert(DetectMode(), Equals, Kitty)
+
+	os.Setenv("TERM", "foot")
+	c.Assert(DetectMode(), Equals, Sixel)
+
+	os.Setenv(ModeENV, "halfblocks")
+	c.Assert(DetectMode(), Equals, HalfBlocks)
+}
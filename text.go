@@ -0,0 +1,114 @@
+package rgbmatrix
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// DefaultTextSpeed is the speed, in pixels per second, at which a text that
+// doesn't fit is scrolled when no Speed is given
+const DefaultTextSpeed = 30
+
+// TextAnimation is an Animation rendering a line of text, the text is centered
+// if it fits in Size or scrolled from right to left, in a loop, if it doesn't.
+// The Animation never ends.
+type TextAnimation struct {
+	Text string
+	// Size of the frames, usually the Canvas size
+	Size image.Point
+	// Face used to draw the text, basicfont.Face7x13 if nil
+	Face font.Face
+	// Color of the text, white if nil
+	Color color.Color
+	// Background color of the frames, black if nil
+	Background color.Color
+	// Speed in pixels per second of the scroll, DefaultTextSpeed if zero
+	Speed int
+
+	Clocked
+	frame  *image.RGBA
+	offset int
+}
+
+// NewTextAnimation returns a new TextAnimation of the given text and size
+func NewTextAnimation(text string, size image.Point) *TextAnimation {
+	return &TextAnimation{Text: text, Size: size}
+}
+
+// Next returns the next frame of the text
+func (a *TextAnimation) Next() (image.Image, <-chan time.Time, error) {
+	if a.frame == nil || a.frame.Bounds().Size() != a.Size {
+		a.frame = image.NewRGBA(image.Rect(0, 0, a.Size.X, a.Size.Y))
+		a.offset = a.Size.X
+	}
+
+	bg := a.Background
+	if bg == nil {
+		bg = color.Black
+	}
+
+	draw.Draw(a.frame, a.frame.Bounds(), &image.Uniform{bg}, image.ZP, draw.Src)
+
+	width := MeasureText(a.Text, a.face())
+	if width <= a.Size.X {
+		DrawText(a.frame, a.Text, a.face(), a.color(), (a.Size.X-width)/2)
+		return a.frame, a.Clock().After(time.Second), nil
+	}
+
+	DrawText(a.frame, a.Text, a.face(), a.color(), a.offset)
+	a.offset--
+	if a.offset < -width {
+		a.offset = a.Size.X
+	}
+
+	speed := a.Speed
+	if speed <= 0 {
+		speed = DefaultTextSpeed
+	}
+
+	return a.frame, a.Clock().After(time.Second / time.Duration(speed)), nil
+}
+
+func (a *TextAnimation) face() font.Face {
+	if a.Face == nil {
+		return basicfont.Face7x13
+	}
+
+	return a.Face
+}
+
+func (a *TextAnimation) color() color.Color {
+	if a.Color == nil {
+		return color.White
+	}
+
+	return a.Color
+}
+
+// MeasureText returns the width in pixels of the given text
+func MeasureText(text string, face font.Face) int {
+	return font.MeasureString(face, text).Ceil()
+}
+
+// DrawText draws a line of text into dst, starting at x and vertically
+// centered in the bounds of dst
+func DrawText(dst draw.Image, text string, face font.Face, c color.Color, x int) {
+	b := dst.Bounds()
+	m := face.Metrics()
+	y := b.Min.Y + (b.Dy()+m.Ascent.Ceil()-m.Descent.Ceil())/2
+
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  &image.Uniform{c},
+		Face: face,
+		Dot:  fixed.P(b.Min.X+x, y),
+	}
+
+	d.DrawString(text)
+}
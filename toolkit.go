@@ -21,6 +21,17 @@ type ToolKit struct {
 	//		return imaging.Fill(img, 64, 96, imaging.Center, imaging.Lanczos)
 	//	}
 	Transform func(img image.Image) *image.NRGBA
+
+	// Fit if present adjusts the images to the Canvas size, after Transform is
+	// applied, this is a small example:
+	//	tk.Fit = &rgbmatrix.Fit{Mode: rgbmatrix.FitContain, Resampling: rgbmatrix.Bilinear}
+	Fit *Fit
+
+	// Clock used to time the images and the transitions, SystemClock if nil.
+	// It is set to the Animations played, so a FakeClock can drive them.
+	Clock Clock
+
+	notifications notificationQueue
 }
 
 // NewToolKit returns a new ToolKit wrapping the given Matrix
@@ -32,15 +43,10 @@ func NewToolKit(m Matrix) *ToolKit {
 
 // PlayImage draws the given image during the given delay
 func (tk *ToolKit) PlayImage(i image.Image, delay time.Duration) error {
-	start := time.Now()
-	defer func() { time.Sleep(delay - time.Since(start)) }()
-
-	if tk.Transform != nil {
-		i = tk.Transform(i)
-	}
+	timer := tk.clock().NewTimer(delay)
+	defer timer.Stop()
 
-	draw.Draw(tk.Canvas, tk.Canvas.Bounds(), i, image.ZP, draw.Over)
-	return tk.Canvas.Render()
+	return tk.PlayImageUntil(i, timer.C())
 }
 
 type Animation interface {
@@ -54,6 +60,7 @@ func (tk *ToolKit) PlayAnimation(a Animation) error {
 	var i image.Image
 	var n <-chan time.Time
 
+	SetClock(a, tk.Clock)
 	for {
 		i, n, err = a.Next()
 		if err != nil {
@@ -74,16 +81,13 @@ func (tk *ToolKit) PlayAnimation(a Animation) error {
 
 // PlayImageUntil draws the given image until is notified to stop
 func (tk *ToolKit) PlayImageUntil(i image.Image, notify <-chan time.Time) error {
-	defer func() {
-		<-notify
-	}()
-
-	if tk.Transform != nil {
-		i = tk.Transform(i)
+	i = tk.prepare(i)
+	err := tk.render(i)
+	if werr := tk.wait(i, notify); err == nil {
+		err = werr
 	}
 
-	draw.Draw(tk.Canvas, tk.Canvas.Bounds(), i, image.ZP, draw.Over)
-	return tk.Canvas.Render()
+	return err
 }
 
 // PlayImages draws a sequence of images during the given delays, the len of
@@ -136,6 +140,29 @@ func (tk *ToolKit) PlayGIF(r io.Reader) (chan bool, error) {
 	return tk.PlayImages(images, delay, gif.LoopCount), nil
 }
 
+// prepare applies Transform and Fit to the given image
+func (tk *ToolKit) prepare(i image.Image) image.Image {
+	if tk.Transform != nil {
+		i = tk.Transform(i)
+	}
+
+	if tk.Fit != nil {
+		i = tk.Fit.Apply(i, tk.Canvas.Bounds().Size())
+	}
+
+	return i
+}
+
+// render draws the given image, as is, into the Canvas and renders it
+func (tk *ToolKit) render(i image.Image) error {
+	draw.Draw(tk.Canvas, tk.Canvas.Bounds(), i, image.ZP, draw.Over)
+	return tk.Canvas.Render()
+}
+
+func (tk *ToolKit) clock() Clock {
+	return ClockOrSystem(tk.Clock)
+}
+
 // Close close the toolkit and the inner canvas
 func (tk *ToolKit) Close() error {
 	return tk.Canvas.Close()
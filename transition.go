@@ -0,0 +1,341 @@
+package rgbmatrix
+
+import (
+	"image"
+	"image/draw"
+	"io"
+	"math"
+	"time"
+)
+
+// DefaultTransitionFrameRate frames per second rendered by a Transition when
+// no FrameRate is given
+const DefaultTransitionFrameRate = 30
+
+// Transition describes how to go from one image to other
+type Transition struct {
+	// Effect renders every frame of the transition, Crossfade if nil
+	Effect Effect
+	// Duration of the transition
+	Duration time.Duration
+	// Easing applied to the progress of the transition, Linear if nil
+	Easing Easing
+	// FrameRate frames per second rendered, DefaultTransitionFrameRate if zero
+	FrameRate int
+}
+
+func (t *Transition) interval() time.Duration {
+	fps := t.FrameRate
+	if fps <= 0 {
+		fps = DefaultTransitionFrameRate
+	}
+
+	return time.Second / time.Duration(fps)
+}
+
+// progress returns the eased progress of the transition after elapsed
+func (t *Transition) progress(elapsed time.Duration) float64 {
+	p := 1.0
+	if t.Duration > 0 {
+		p = clampf(float64(elapsed)/float64(t.Duration), 0, 1)
+	}
+
+	if t.Easing == nil {
+		return p
+	}
+
+	return t.Easing(p)
+}
+
+// render writes to dst the frame of the transition at the given progress,
+// from and to are drawn at the top-left corner of dst
+func (t *Transition) render(dst *image.RGBA, from, to image.Image, progress float64) {
+	e := t.Effect
+	if e == nil {
+		e = Crossfade
+	}
+
+	e.Render(dst, toRGBA(from, dst.Bounds()), toRGBA(to, dst.Bounds()), progress)
+}
+
+// toRGBA returns img as a *image.RGBA with the given bounds
+func toRGBA(img image.Image, r image.Rectangle) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds() == r {
+		return rgba
+	}
+
+	rgba := image.NewRGBA(r)
+	if img != nil {
+		draw.Draw(rgba, r, img, img.Bounds().Min, draw.Src)
+	}
+
+	return rgba
+}
+
+// PlayTransition plays the transition t from the image from to the image to,
+// to remains drawn when the transition ends
+func (tk *ToolKit) PlayTransition(from, to image.Image, t *Transition) error {
+	return tk.playTransition(tk.prepare(from), tk.prepare(to), t)
+}
+
+// playTransition plays the transition t between two images already prepared
+func (tk *ToolKit) playTransition(from, to image.Image, t *Transition) error {
+	frame := image.NewRGBA(tk.Canvas.Bounds())
+
+	clock := tk.clock()
+	start := clock.Now()
+	for {
+		elapsed := clock.Now().Sub(start)
+		if elapsed >= t.Duration {
+			break
+		}
+
+		t.render(frame, from, to, t.progress(elapsed))
+		if err := tk.render(frame); err != nil {
+			return err
+		}
+
+		clock.Sleep(t.interval() - (clock.Now().Sub(start) - elapsed))
+	}
+
+	return tk.render(to)
+}
+
+// NewTransitionAnimation returns an Animation that plays from until io.EOF is
+// returned, then plays to, using the transition t between the last frame of
+// from and the frames of to
+func NewTransitionAnimation(from, to Animation, t *Transition) Animation {
+	return &transitionAnimation{from: from, to: to, t: t}
+}
+
+type transitionAnimation struct {
+	Clocked
+	from, to Animation
+	t        *Transition
+
+	start   time.Time
+	last    image.Image
+	current image.Image
+	next    <-chan time.Time
+	done    bool
+}
+
+func (a *transitionAnimation) Next() (image.Image, <-chan time.Time, error) {
+	if a.done {
+		return a.to.Next()
+	}
+
+	if a.start.IsZero() {
+		if i, n, err := a.from.Next(); err != io.EOF {
+			a.last = i
+			return i, n, err
+		}
+
+		if err := a.nextTo(); err != nil {
+			return nil, nil, err
+		}
+
+		a.start = a.Clock().Now()
+	} else {
+		select {
+		case <-a.next:
+			if err := a.nextTo(); err != nil {
+				return nil, nil, err
+			}
+		default:
+		}
+	}
+
+	elapsed := a.Clock().Now().Sub(a.start)
+	if elapsed >= a.t.Duration {
+		a.done = true
+		return a.current, a.next, nil
+	}
+
+	b := a.current.Bounds()
+	frame := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	a.t.render(frame, a.last, a.current, a.t.progress(elapsed))
+
+	return frame, a.Clock().After(a.t.interval()), nil
+}
+
+func (a *transitionAnimation) Reset() {
+	reset(a.from)
+	reset(a.to)
+	a.start, a.last, a.current, a.next, a.done = time.Time{}, nil, nil, nil, false
+}
+
+func (a *transitionAnimation) SetClock(c Clock) {
+	a.Clocked.SetClock(c)
+	SetClock(a.from, c)
+	SetClock(a.to, c)
+}
+
+func (a *transitionAnimation) nextTo() error {
+	i, n, err := a.to.Next()
+	if err != nil {
+		return err
+	}
+
+	a.current, a.next = i, n
+	return nil
+}
+
+// Effect renders a frame of a transition, from and to has the same bounds as
+// dst, progress goes from 0, only from is visible, to 1, only to is visible
+type Effect interface {
+	Render(dst, from, to *image.RGBA, progress float64)
+}
+
+// EffectFunc is an adapter to allow the use of ordinary functions as Effect
+type EffectFunc func(dst, from, to *image.RGBA, progress float64)
+
+// Render calls f(dst, from, to, progress)
+func (f EffectFunc) Render(dst, from, to *image.RGBA, progress float64) {
+	f(dst, from, to, progress)
+}
+
+// Direction of the movement of a transition
+type Direction int
+
+const (
+	DirectionLeft Direction = iota
+	DirectionRight
+	DirectionUp
+	DirectionDown
+)
+
+// offset returns the translation of a movement in the direction d of the
+// given fraction of size
+func (d Direction) offset(size image.Point, f float64) image.Point {
+	switch d {
+	case DirectionLeft:
+		return image.Pt(-round(float64(size.X)*f), 0)
+	case DirectionRight:
+		return image.Pt(round(float64(size.X)*f), 0)
+	case DirectionUp:
+		return image.Pt(0, -round(float64(size.Y)*f))
+	default:
+		return image.Pt(0, round(float64(size.Y)*f))
+	}
+}
+
+// Crossfade blends linearly from and to
+var Crossfade Effect = EffectFunc(func(dst, from, to *image.RGBA, p float64) {
+	for i := range dst.Pix {
+		dst.Pix[i] = uint8(float64(from.Pix[i])*(1-p) + float64(to.Pix[i])*p + .5)
+	}
+})
+
+// Dissolve replaces the pixels of from with the pixels of to in a random
+// but stable order
+var Dissolve Effect = EffectFunc(func(dst, from, to *image.RGBA, p float64) {
+	b := dst.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			src := from
+			if noise(x, y) < p {
+				src = to
+			}
+
+			copyPixel(dst, src, x, y)
+		}
+	}
+})
+
+// Iris reveals to in a circle growing from the center
+var Iris Effect = EffectFunc(func(dst, from, to *image.RGBA, p float64) {
+	b := dst.Bounds()
+	cx, cy := float64(b.Min.X+b.Max.X)/2, float64(b.Min.Y+b.Max.Y)/2
+	r := p * math.Hypot(float64(b.Dx())/2, float64(b.Dy())/2)
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			src := from
+			if math.Hypot(float64(x)+.5-cx, float64(y)+.5-cy) < r {
+				src = to
+			}
+
+			copyPixel(dst, src, x, y)
+		}
+	}
+})
+
+// Pixelate pixelates from in blocks growing until the middle of the
+// transition, and then unpixelates to
+var Pixelate Effect = EffectFunc(func(dst, from, to *image.RGBA, p float64) {
+	b := dst.Bounds()
+	src := from
+	if p >= .5 {
+		src = to
+	}
+
+	max := b.Dx()
+	if b.Dy() > max {
+		max = b.Dy()
+	}
+
+	block := 1 + int((1-math.Abs(2*p-1))*float64(max/4))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			bx := b.Min.X + (x-b.Min.X)/block*block
+			by := b.Min.Y + (y-b.Min.Y)/block*block
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(bx, by):])
+		}
+	}
+})
+
+// Wipe reveals to with an edge moving in the direction d
+func Wipe(d Direction) Effect {
+	return EffectFunc(func(dst, from, to *image.RGBA, p float64) {
+		b := dst.Bounds()
+		revealed := b.Add(d.offset(b.Size(), p-1))
+
+		draw.Draw(dst, b, from, b.Min, draw.Src)
+		draw.Draw(dst, revealed, to, revealed.Min, draw.Src)
+	})
+}
+
+// Slide moves to in the direction d over from
+func Slide(d Direction) Effect {
+	return EffectFunc(func(dst, from, to *image.RGBA, p float64) {
+		b := dst.Bounds()
+
+		draw.Draw(dst, b, from, b.Min, draw.Src)
+		draw.Draw(dst, b.Add(d.offset(b.Size(), p-1)), to, b.Min, draw.Src)
+	})
+}
+
+// Push moves from and to in the direction d, to pushing from out
+func Push(d Direction) Effect {
+	return EffectFunc(func(dst, from, to *image.RGBA, p float64) {
+		b := dst.Bounds()
+
+		draw.Draw(dst, b.Add(d.offset(b.Size(), p)), from, b.Min, draw.Src)
+		draw.Draw(dst, b.Add(d.offset(b.Size(), p-1)), to, b.Min, draw.Src)
+	})
+}
+
+func copyPixel(dst, src *image.RGBA, x, y int) {
+	i := dst.PixOffset(x, y)
+	copy(dst.Pix[i:i+4], src.Pix[src.PixOffset(x, y):])
+}
+
+// noise returns a stable pseudo-random value in the range [0, 1) for x, y
+func noise(x, y int) float64 {
+	h := uint32(x)*374761393 + uint32(y)*668265263
+	h = (h ^ (h >> 13)) * 1274126177
+	h ^= h >> 16
+
+	return float64(h) / (1 << 32)
+}
+
+// Easing maps the linear progress of a transition, in the range [0, 1], to
+// the progress applied to an Effect. The tween package has the usual easing
+// functions, as tween.InOutQuad.
+type Easing func(t float64) float64
+
+// Linear is the identity easing
+func Linear(t float64) float64 {
+	return t
+}
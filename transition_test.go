@@ -0,0 +1,99 @@
+package rgbmatrix
+
+import (
+	"image"
+	"image/color"
+	"io"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type TransitionSuite struct{}
+
+var _ = Suite(&TransitionSuite{})
+
+func newUniformRGBA(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < w*h; i++ {
+		img.Set(i%w, i/w, c)
+	}
+
+	return img
+}
+
+func (s *TransitionSuite) render(e Effect, p float64) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	e.Render(dst, newUniformRGBA(4, 2, red), newUniformRGBA(4, 2, blue), p)
+	return dst
+}
+
+func (s *TransitionSuite) TestCrossfade(c *C) {
+	dst := s.render(Crossfade, .5)
+	c.Assert(dst.At(0, 0), Equals, color.RGBA{128, 0, 128, 255})
+}
+
+func (s *TransitionSuite) TestWipe(c *C) {
+	dst := s.render(Wipe(DirectionLeft), .25)
+	c.Assert(dst.At(2, 0), Equals, red)
+	c.Assert(dst.At(3, 1), Equals, blue)
+
+	dst = s.render(Wipe(DirectionDown), .5)
+	c.Assert(dst.At(0, 0), Equals, blue)
+	c.Assert(dst.At(0, 1), Equals, red)
+}
+
+func (s *TransitionSuite) TestPush(c *C) {
+	dst := s.render(Push(DirectionRight), .5)
+	c.Assert(dst.At(1, 0), Equals, blue)
+	c.Assert(dst.At(2, 0), Equals, red)
+}
+
+func (s *TransitionSuite) TestBounds(c *C) {
+	for _, e := range []Effect{Dissolve, Iris, Pixelate, Slide(DirectionUp)} {
+		c.Assert(s.render(e, 0).At(1, 1), Equals, red)
+		c.Assert(s.render(e, 1).At(1, 1), Equals, blue)
+	}
+}
+
+func (s *TransitionSuite) TestDefaultEffect(c *C) {
+	t := &Transition{}
+	dst := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	t.render(dst, newUniformRGBA(4, 2, red), newUniformRGBA(4, 2, blue), .5)
+	c.Assert(dst.At(0, 0), Equals, color.RGBA{128, 0, 128, 255})
+}
+
+type imagesAnimation []image.Image
+
+func (a *imagesAnimation) Next() (image.Image, <-chan time.Time, error) {
+	if len(*a) == 0 {
+		return nil, nil, io.EOF
+	}
+
+	i := (*a)[0]
+	*a = (*a)[1:]
+	return i, time.After(0), nil
+}
+
+func (s *TransitionSuite) TestTransitionAnimation(c *C) {
+	from := &imagesAnimation{newUniformRGBA(4, 2, red)}
+	to := &imagesAnimation{newUniformRGBA(4, 2, blue), newUniformRGBA(4, 2, black)}
+
+	a := NewTransitionAnimation(from, to, &Transition{Effect: Crossfade})
+
+	i, _, err := a.Next()
+	c.Assert(err, IsNil)
+	c.Assert(i.At(0, 0), Equals, red)
+
+	i, n, err := a.Next()
+	c.Assert(err, IsNil)
+	c.Assert(i.At(0, 0), Equals, blue)
+	<-n
+
+	i, _, err = a.Next()
+	c.Assert(err, IsNil)
+	c.Assert(i.At(0, 0), Equals, black)
+
+	_, _, err = a.Next()
+	c.Assert(err, Equals, io.EOF)
+}
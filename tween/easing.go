@@ -0,0 +1,106 @@
+package tween
+
+import (
+	"math"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+)
+
+// The easing functions, as described in https://easings.net, all of them can
+// be used as rgbmatrix.Easing, for example in a Transition. Elastic and Back
+// overshoot the range [0, 1].
+var (
+	Linear rgbmatrix.Easing = rgbmatrix.Linear
+
+	InQuad    rgbmatrix.Easing = func(t float64) float64 { return t * t }
+	OutQuad                    = reverse(InQuad)
+	InOutQuad                  = inOut(InQuad)
+
+	InCubic    rgbmatrix.Easing = func(t float64) float64 { return t * t * t }
+	OutCubic                    = reverse(InCubic)
+	InOutCubic                  = inOut(InCubic)
+
+	InQuart    rgbmatrix.Easing = func(t float64) float64 { return t * t * t * t }
+	OutQuart                    = reverse(InQuart)
+	InOutQuart                  = inOut(InQuart)
+
+	InSine    rgbmatrix.Easing = func(t float64) float64 { return 1 - math.Cos(t*math.Pi/2) }
+	OutSine                    = reverse(InSine)
+	InOutSine                  = inOut(InSine)
+
+	InExpo    rgbmatrix.Easing = inExpo
+	OutExpo                    = reverse(InExpo)
+	InOutExpo                  = inOut(InExpo)
+
+	InCirc    rgbmatrix.Easing = func(t float64) float64 { return 1 - math.Sqrt(1-t*t) }
+	OutCirc                    = reverse(InCirc)
+	InOutCirc                  = inOut(InCirc)
+
+	InBack    rgbmatrix.Easing = inBack
+	OutBack                    = reverse(InBack)
+	InOutBack                  = inOut(InBack)
+
+	InElastic    rgbmatrix.Easing = inElastic
+	OutElastic                    = reverse(InElastic)
+	InOutElastic                  = inOut(InElastic)
+
+	InBounce                     = reverse(OutBounce)
+	OutBounce   rgbmatrix.Easing = outBounce
+	InOutBounce                  = inOut(InBounce)
+)
+
+// reverse returns the easing out from the given easing in, and vice versa
+func reverse(e rgbmatrix.Easing) rgbmatrix.Easing {
+	return func(t float64) float64 {
+		return 1 - e(1-t)
+	}
+}
+
+// inOut returns the easing in and out from the given easing in
+func inOut(in rgbmatrix.Easing) rgbmatrix.Easing {
+	return func(t float64) float64 {
+		if t < .5 {
+			return in(2*t) / 2
+		}
+
+		return 1 - in(2-2*t)/2
+	}
+}
+
+func inExpo(t float64) float64 {
+	if t <= 0 {
+		return 0
+	}
+
+	return math.Pow(2, 10*t-10)
+}
+
+func inBack(t float64) float64 {
+	const c = 1.70158
+	return (c+1)*t*t*t - c*t*t
+}
+
+func inElastic(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+
+	return -math.Pow(2, 10*t-10) * math.Sin((t*10-10.75)*2*math.Pi/3)
+}
+
+func outBounce(t float64) float64 {
+	const n, d = 7.5625, 2.75
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + .75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + .9375
+	default:
+		t -= 2.625 / d
+		return n*t*t + .984375
+	}
+}
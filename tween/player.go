@@ -0,0 +1,119 @@
+package tween
+
+import (
+	"image"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+)
+
+// DefaultFrameRate is the number of updates per second of the tweens played,
+// when no frame rate is given
+const DefaultFrameRate = 30
+
+// Animation is an Animation updating a Tween before rendering every frame, it
+// ends after rendering the frame with the final values of the tween
+type Animation struct {
+	rgbmatrix.Clocked
+	// Tween updated every frame
+	Tween Tween
+	// Render returns the frame, with the properties already updated
+	Render func() image.Image
+	// FrameRate is the frames per second rendered, DefaultFrameRate if zero
+	FrameRate int
+
+	start time.Time
+	done  bool
+}
+
+// NewAnimation returns a new Animation of the tween t rendered by render, for
+// example, to play a text sliding in with a ToolKit:
+//
+//	var x int
+//	t := tween.Int(64, 0, time.Second, tween.OutCubic, func(v int) { x = v })
+//	tk.PlayAnimation(tween.NewAnimation(t, func() image.Image {
+//		frame := image.NewRGBA(tk.Canvas.Bounds())
+//		rgbmatrix.DrawText(frame, "hello", basicfont.Face7x13, color.White, x)
+//		return frame
+//	}))
+func NewAnimation(t Tween, render func() image.Image) *Animation {
+	return &Animation{Tween: t, Render: render}
+}
+
+// Next updates the tween and renders the frame
+func (a *Animation) Next() (image.Image, <-chan time.Time, error) {
+	if a.done {
+		return nil, nil, io.EOF
+	}
+
+	clock := a.Clock()
+	now := clock.Now()
+	if a.start.IsZero() {
+		a.start = now
+	}
+
+	a.done = a.Tween.Set(now.Sub(a.start))
+	return a.Render(), clock.After(interval(a.FrameRate) - clock.Now().Sub(now)), nil
+}
+
+// Player updates a Tween in the background, useful when the properties belong
+// to something rendering by itself, as a Compositor layer. The setters are
+// called from the goroutine of the Player, so they must be safe for
+// concurrent use.
+type Player struct {
+	stop sync.Once
+	quit chan struct{}
+	done chan struct{}
+}
+
+// Play starts updating the tween t in a goroutine, frameRate times per
+// second, DefaultFrameRate if zero
+func Play(t Tween, frameRate int) *Player {
+	return PlayClock(t, frameRate, rgbmatrix.SystemClock{})
+}
+
+// PlayClock is like Play, using the given Clock to update the tween
+func PlayClock(t Tween, frameRate int, clock rgbmatrix.Clock) *Player {
+	p := &Player{quit: make(chan struct{}), done: make(chan struct{})}
+
+	go func() {
+		defer close(p.done)
+
+		timer := clock.NewTimer(interval(frameRate))
+		defer timer.Stop()
+
+		start := clock.Now()
+		for !t.Set(clock.Now().Sub(start)) {
+			select {
+			case <-timer.C():
+				timer.Reset(interval(frameRate))
+			case <-p.quit:
+				return
+			}
+		}
+	}()
+
+	return p
+}
+
+// Stop stops updating the tween, the properties keep their current values.
+// It is safe to call it several times, even concurrently.
+func (p *Player) Stop() {
+	p.stop.Do(func() { close(p.quit) })
+	<-p.done
+}
+
+// Done returns a channel closed when the tween is done or stopped
+func (p *Player) Done() <-chan struct{} {
+	return p.done
+}
+
+func interval(frameRate int) time.Duration {
+	if frameRate <= 0 {
+		return time.Second / DefaultFrameRate
+	}
+
+	return time.Second / time.Duration(frameRate)
+}
@@ -0,0 +1,212 @@
+// Package tween animates properties, like the position of a sprite or the
+// opacity of a layer, from a value to another over time following an easing
+// function. The tweens can be sequenced and run in parallel, and are played
+// as an Animation or in the background.
+//
+// The properties are updated by a setter, so methods as Layer.SetPosition or
+// Layer.SetOpacity can be used directly:
+//
+//	t := tween.Sequence(
+//		tween.Point(image.Pt(-16, 8), image.Pt(24, 8), time.Second, tween.OutBounce, layer.SetPosition),
+//		tween.Number(1, 0, time.Second, tween.Linear, layer.SetOpacity),
+//	)
+//
+// A Player calls the setters from its own goroutine, so they must be safe for
+// concurrent use, as the methods of Layer. The setters that aren't, as
+// Sprite.MoveTo, can be used with an Animation, which calls them from the
+// goroutine playing it, before rendering every frame.
+package tween
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+)
+
+// Tween updates properties over time
+type Tween interface {
+	// Set updates the properties to their value at the given time elapsed
+	// since the start of the tween, returns true once the tween is done
+	Set(elapsed time.Duration) bool
+	// Duration of the tween
+	Duration() time.Duration
+}
+
+// Func returns a Tween calling f with the progress, in the range [0, 1] after
+// applying the easing, during the given duration. If e is nil Linear is used.
+func Func(d time.Duration, e rgbmatrix.Easing, f func(p float64)) Tween {
+	if e == nil {
+		e = Linear
+	}
+
+	return &tween{d: d, easing: e, f: f}
+}
+
+// Number returns a Tween calling set with a number from one value to another
+func Number(from, to float64, d time.Duration, e rgbmatrix.Easing, set func(float64)) Tween {
+	return Func(d, e, func(p float64) {
+		set(lerp(from, to, p))
+	})
+}
+
+// Int returns a Tween calling set with an integer from one value to another
+func Int(from, to int, d time.Duration, e rgbmatrix.Easing, set func(int)) Tween {
+	return Func(d, e, func(p float64) {
+		set(round(lerp(float64(from), float64(to), p)))
+	})
+}
+
+// Point returns a Tween calling set with a point moving from one position to
+// another
+func Point(from, to image.Point, d time.Duration, e rgbmatrix.Easing, set func(image.Point)) Tween {
+	return Func(d, e, func(p float64) {
+		set(image.Pt(
+			round(lerp(float64(from.X), float64(to.X), p)),
+			round(lerp(float64(from.Y), float64(to.Y), p)),
+		))
+	})
+}
+
+// Color returns a Tween calling set with a color fading from one color to
+// another
+func Color(from, to color.Color, d time.Duration, e rgbmatrix.Easing, set func(color.Color)) Tween {
+	a := color.RGBA64Model.Convert(from).(color.RGBA64)
+	b := color.RGBA64Model.Convert(to).(color.RGBA64)
+
+	return Func(d, e, func(p float64) {
+		set(color.RGBA64{
+			R: channel(lerp(float64(a.R), float64(b.R), p)),
+			G: channel(lerp(float64(a.G), float64(b.G), p)),
+			B: channel(lerp(float64(a.B), float64(b.B), p)),
+			A: channel(lerp(float64(a.A), float64(b.A), p)),
+		})
+	})
+}
+
+// Delay returns a Tween doing nothing during the given duration, useful in
+// sequences
+func Delay(d time.Duration) Tween {
+	return Func(d, Linear, func(float64) {})
+}
+
+type tween struct {
+	d      time.Duration
+	easing rgbmatrix.Easing
+	f      func(p float64)
+}
+
+func (t *tween) Set(elapsed time.Duration) bool {
+	if elapsed >= t.d {
+		t.f(t.easing(1))
+		return true
+	}
+
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	t.f(t.easing(float64(elapsed) / float64(t.d)))
+	return false
+}
+
+func (t *tween) Duration() time.Duration {
+	return t.d
+}
+
+// Sequence returns a Tween playing the given tweens one after the other
+func Sequence(tweens ...Tween) Tween {
+	return sequence(tweens)
+}
+
+type sequence []Tween
+
+func (s sequence) Set(elapsed time.Duration) bool {
+	for _, t := range s {
+		d := t.Duration()
+		if elapsed < d {
+			t.Set(elapsed)
+			return false
+		}
+
+		t.Set(d)
+		elapsed -= d
+	}
+
+	return true
+}
+
+func (s sequence) Duration() time.Duration {
+	var d time.Duration
+	for _, t := range s {
+		d += t.Duration()
+	}
+
+	return d
+}
+
+// Parallel returns a Tween playing the given tweens at the same time, it is
+// done when all of them are done
+func Parallel(tweens ...Tween) Tween {
+	return parallel(tweens)
+}
+
+type parallel []Tween
+
+func (p parallel) Set(elapsed time.Duration) bool {
+	done := true
+	for _, t := range p {
+		done = t.Set(elapsed) && done
+	}
+
+	return done
+}
+
+func (p parallel) Duration() time.Duration {
+	var d time.Duration
+	for _, t := range p {
+		if td := t.Duration(); td > d {
+			d = td
+		}
+	}
+
+	return d
+}
+
+// Repeat returns a Tween playing the given tween n times
+func Repeat(t Tween, n int) Tween {
+	return &repeat{t: t, n: n}
+}
+
+type repeat struct {
+	t Tween
+	n int
+}
+
+func (r *repeat) Set(elapsed time.Duration) bool {
+	d := r.t.Duration()
+	if d <= 0 || elapsed >= r.Duration() {
+		return r.t.Set(d)
+	}
+
+	r.t.Set(elapsed % d)
+	return false
+}
+
+func (r *repeat) Duration() time.Duration {
+	return r.t.Duration() * time.Duration(r.n)
+}
+
+func lerp(a, b, p float64) float64 {
+	return a + (b-a)*p
+}
+
+func round(v float64) int {
+	return int(math.Floor(v + .5))
+}
+
+func channel(v float64) uint16 {
+	return uint16(math.Max(0, math.Min(0xffff, v+.5)))
+}
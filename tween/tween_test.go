@@ -0,0 +1,146 @@
+package tween
+
+import (
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type TweenSuite struct{}
+
+var _ = Suite(&TweenSuite{})
+
+func (s *TweenSuite) TestEasingBounds(c *C) {
+	for _, e := range []func(float64) float64{
+		Linear, InQuad, OutQuad, InOutQuad, InCubic, OutCubic, InOutCubic,
+		InQuart, OutQuart, InOutQuart, InSine, OutSine, InOutSine,
+		InExpo, OutExpo, InOutExpo, InCirc, OutCirc, InOutCirc,
+		InBack, OutBack, InOutBack, InElastic, OutElastic, InOutElastic,
+		InBounce, OutBounce, InOutBounce,
+	} {
+		c.Assert(math.Abs(e(0)) < 1e-3, Equals, true)
+		c.Assert(math.Abs(e(1)-1) < 1e-3, Equals, true)
+	}
+
+	c.Assert(InOutQuad(.25), Equals, .125)
+	c.Assert(OutQuad(.5), Equals, .75)
+}
+
+func (s *TweenSuite) TestNumber(c *C) {
+	var v float64
+	t := Number(10, 20, time.Second, nil, func(n float64) { v = n })
+
+	c.Assert(t.Set(500*time.Millisecond), Equals, false)
+	c.Assert(v, Equals, 15.)
+
+	c.Assert(t.Set(2*time.Second), Equals, true)
+	c.Assert(v, Equals, 20.)
+}
+
+func (s *TweenSuite) TestPointAndColor(c *C) {
+	var p image.Point
+	var col color.Color
+
+	t := Parallel(
+		Point(image.Pt(0, 0), image.Pt(10, -10), time.Second, Linear, func(v image.Point) { p = v }),
+		Color(color.Black, color.White, 2*time.Second, Linear, func(v color.Color) { col = v }),
+	)
+
+	c.Assert(t.Duration(), Equals, 2*time.Second)
+	c.Assert(t.Set(time.Second), Equals, false)
+	c.Assert(p, Equals, image.Pt(10, -10))
+	c.Assert(color.RGBAModel.Convert(col), Equals, color.RGBA{128, 128, 128, 255})
+
+	c.Assert(t.Set(2*time.Second), Equals, true)
+	c.Assert(color.RGBAModel.Convert(col), Equals, color.RGBA{255, 255, 255, 255})
+}
+
+func (s *TweenSuite) TestSequence(c *C) {
+	var a, b int
+	t := Sequence(
+		Int(0, 10, time.Second, Linear, func(v int) { a = v }),
+		Delay(time.Second),
+		Int(0, 10, time.Second, Linear, func(v int) { b = v }),
+	)
+
+	c.Assert(t.Duration(), Equals, 3*time.Second)
+	c.Assert(t.Set(1500*time.Millisecond), Equals, false)
+	c.Assert(a, Equals, 10)
+	c.Assert(b, Equals, 0)
+
+	c.Assert(t.Set(2500*time.Millisecond), Equals, false)
+	c.Assert(b, Equals, 5)
+
+	c.Assert(t.Set(3*time.Second), Equals, true)
+	c.Assert(b, Equals, 10)
+}
+
+func (s *TweenSuite) TestRepeat(c *C) {
+	var v int
+	t := Repeat(Int(0, 10, time.Second, Linear, func(n int) { v = n }), 3)
+
+	c.Assert(t.Duration(), Equals, 3*time.Second)
+	c.Assert(t.Set(2500*time.Millisecond), Equals, false)
+	c.Assert(v, Equals, 5)
+	c.Assert(t.Set(3*time.Second), Equals, true)
+	c.Assert(v, Equals, 10)
+}
+
+func (s *TweenSuite) TestAnimation(c *C) {
+	var v int
+	a := NewAnimation(Int(0, 10, 0, Linear, func(n int) { v = n }), func() image.Image {
+		return image.NewRGBA(image.Rect(0, 0, v, 1))
+	})
+
+	img, _, err := a.Next()
+	c.Assert(err, IsNil)
+	c.Assert(img.Bounds().Dx(), Equals, 10)
+
+	_, _, err = a.Next()
+	c.Assert(err, Equals, io.EOF)
+}
+
+func (s *TweenSuite) TestPlay(c *C) {
+	values := make(chan int, 100)
+	p := Play(Int(0, 10, 50*time.Millisecond, Linear, func(n int) { values <- n }), 100)
+
+	select {
+	case <-p.Done():
+	case <-time.After(time.Second):
+		c.Fatal("tween not done")
+	}
+
+	p.Stop()
+	close(values)
+
+	var last int
+	for v := range values {
+		last = v
+	}
+
+	c.Assert(last, Equals, 10)
+}
+
+func (s *TweenSuite) TestStopConcurrently(c *C) {
+	p := Play(Int(0, 10, time.Hour, Linear, func(int) {}), 100)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Stop()
+		}()
+	}
+
+	wg.Wait()
+	<-p.Done()
+}
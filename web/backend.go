@@ -0,0 +1,34 @@
+package web
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+)
+
+func init() {
+	rgbmatrix.RegisterBackend("web", newBackend)
+}
+
+// newBackend returns a Server listening at addr, the pitch parameter sets the
+// PixelPitch, as in web://localhost:8080?pitch=6
+func newBackend(addr string, params url.Values, config *rgbmatrix.HardwareConfig) (rgbmatrix.Matrix, error) {
+	s := NewServer(config.Cols*config.ChainLength, config.Rows*config.Parallel)
+	s.Addr = addr
+	if v := params.Get("pitch"); v != "" {
+		pitch, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pitch parameter %q", v)
+		}
+
+		s.PixelPitch = pitch
+	}
+
+	if err := s.Listen(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
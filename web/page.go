@@ -0,0 +1,74 @@
+package web
+
+// page draws the frames received from /ws on a canvas, with the same gutter
+// and margins as the windowed emulator
+const page = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>RGB led matrix emulator</title>
+<style>
+  body { margin: 0; background: #fff; }
+  canvas { display: block; }
+  #status { font: 12px sans-serif; color: #888; margin: 4px 10px; }
+</style>
+</head>
+<body>
+<canvas id="matrix"></canvas>
+<div id="status">connecting...</div>
+<script>
+(function() {
+  var margin = 10, gutterColor = "rgb(20,20,20)";
+  var canvas = document.getElementById("matrix");
+  var status = document.getElementById("status");
+  var ctx = canvas.getContext("2d");
+  var cfg = null, pitch = 0, gutter = 0;
+
+  function resize() {
+    var p = parseInt(new URLSearchParams(location.search).get("pitch"), 10);
+    pitch = p > 0 ? p : cfg.pitch;
+    gutter = Math.max(1, Math.floor(pitch / 2));
+    canvas.width = cfg.width * (pitch + gutter) - gutter + 2 * margin;
+    canvas.height = cfg.height * (pitch + gutter) - gutter + 2 * margin;
+    ctx.fillStyle = gutterColor;
+    ctx.fillRect(0, 0, canvas.width, canvas.height);
+  }
+
+  function draw(frame) {
+    for (var row = 0; row < cfg.height; row++) {
+      for (var col = 0; col < cfg.width; col++) {
+        var i = (row * cfg.width + col) * 3;
+        ctx.fillStyle = "rgb(" + frame[i] + "," + frame[i + 1] + "," + frame[i + 2] + ")";
+        ctx.fillRect(margin + col * (pitch + gutter), margin + row * (pitch + gutter), pitch, pitch);
+      }
+    }
+  }
+
+  function connect() {
+    var ws = new WebSocket((location.protocol === "https:" ? "wss://" : "ws://") + location.host + "/ws");
+    ws.binaryType = "arraybuffer";
+    ws.onmessage = function(e) {
+      if (typeof e.data === "string") {
+        cfg = JSON.parse(e.data);
+        resize();
+        status.textContent = cfg.width + "x" + cfg.height;
+        return;
+      }
+
+      if (cfg) {
+        draw(new Uint8Array(e.data));
+      }
+    };
+
+    ws.onclose = function() {
+      status.textContent = "disconnected, reconnecting...";
+      setTimeout(connect, 1000);
+    };
+  }
+
+  connect();
+})();
+</script>
+</body>
+</html>
+`
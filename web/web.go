@@ -0,0 +1,275 @@
+// Package web implements a Matrix served to the browsers over HTTP, the page
+// served draws the LEDs on a canvas and receives the frames over a WebSocket.
+// Useful to preview the content of a matrix without the dependencies of the
+// windowed emulator.
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// DefaultAddr is the address listened when no Addr is given
+	DefaultAddr = "localhost:8080"
+	// DefaultPixelPitch is the size in pixels of the LEDs drawn by the
+	// browsers when no PixelPitch is given
+	DefaultPixelPitch = 12
+)
+
+// writeTimeout is the max time sending a frame to a browser can take
+const writeTimeout = 10 * time.Second
+
+// Server is a Matrix streaming the frames to the browsers connected, the
+// pitch of the LEDs can be also changed from the browser, with the pitch query
+// parameter, as in http://localhost:8080/?pitch=6
+type Server struct {
+	Width  int
+	Height int
+	// PixelPitch is the size in pixels of the LEDs, DefaultPixelPitch if zero
+	PixelPitch int
+	// Addr is the address listened by Listen, DefaultAddr if empty
+	Addr string
+
+	leds     []color.Color
+	upgrader websocket.Upgrader
+
+	m       sync.Mutex
+	frame   []byte
+	clients map[*client]bool
+	ln      net.Listener
+	srv     *http.Server
+}
+
+// config is the first message sent to the browsers
+type config struct {
+	Width      int `json:"width"`
+	Height     int `json:"height"`
+	PixelPitch int `json:"pitch"`
+}
+
+// NewServer returns a new Server of w x h leds, the server doesn't listen until
+// Listen is called, or it can be used as a http.Handler
+func NewServer(w, h int) *Server {
+	return &Server{
+		Width:   w,
+		Height:  h,
+		leds:    make([]color.Color, w*h),
+		frame:   make([]byte, w*h*3),
+		clients: make(map[*client]bool),
+	}
+}
+
+// Listen starts serving the page and the frames at Addr, in the background
+func (s *Server) Listen() error {
+	addr := s.Addr
+	if addr == "" {
+		addr = DefaultAddr
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	s.m.Lock()
+	s.ln = ln
+	s.srv = &http.Server{Handler: s}
+	s.m.Unlock()
+
+	go s.srv.Serve(ln)
+	return nil
+}
+
+// URL returns the URL of the page, empty if the server is not listening
+func (s *Server) URL() string {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.ln == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("http://%s/", s.ln.Addr())
+}
+
+// ServeHTTP serves the page at /, and the frames at /ws
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page)
+	case "/ws":
+		s.serveWebSocket(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	pitch := s.PixelPitch
+	if pitch <= 0 {
+		pitch = DefaultPixelPitch
+	}
+
+	c := newClient(conn)
+	err = c.writeJSON(&config{Width: s.Width, Height: s.Height, PixelPitch: pitch})
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	s.m.Lock()
+	s.clients[c] = true
+	c.send(s.frame)
+	s.m.Unlock()
+
+	go c.writeLoop()
+	c.readLoop()
+
+	s.m.Lock()
+	delete(s.clients, c)
+	s.m.Unlock()
+	c.close()
+}
+
+func (s *Server) Geometry() (width, height int) {
+	return s.Width, s.Height
+}
+
+// Apply set all the pixels to the values contained in leds and renders them
+func (s *Server) Apply(leds []color.Color) error {
+	for position, l := range leds {
+		s.Set(position, l)
+	}
+
+	return s.Render()
+}
+
+// Render sends the current leds to all the browsers connected, and sets all of
+// them to black, as the hardware matrix does. The browsers too slow to keep up
+// skip frames.
+func (s *Server) Render() error {
+	defer func() { s.leds = make([]color.Color, s.Width*s.Height) }()
+
+	frame := make([]byte, 0, s.Width*s.Height*3)
+	for i := range s.leds {
+		c := color.RGBAModel.Convert(s.At(i)).(color.RGBA)
+		frame = append(frame, c.R, c.G, c.B)
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.frame = frame
+	for c := range s.clients {
+		c.send(frame)
+	}
+
+	return nil
+}
+
+func (s *Server) At(position int) color.Color {
+	if s.leds[position] == nil {
+		return color.Black
+	}
+
+	return s.leds[position]
+}
+
+func (s *Server) Set(position int, c color.Color) {
+	s.leds[position] = color.RGBAModel.Convert(c)
+}
+
+// Close stops listening and disconnects all the browsers
+func (s *Server) Close() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for c := range s.clients {
+		delete(s.clients, c)
+		c.close()
+	}
+
+	if s.srv == nil {
+		return nil
+	}
+
+	err := s.srv.Close()
+	s.srv, s.ln = nil, nil
+	return err
+}
+
+// client is a browser connected, only the last frame not sent yet is kept
+type client struct {
+	conn   *websocket.Conn
+	frames chan []byte
+	once   sync.Once
+}
+
+func newClient(conn *websocket.Conn) *client {
+	return &client{conn: conn, frames: make(chan []byte, 1)}
+}
+
+// send queues the frame, replacing the previous one if not sent yet
+func (c *client) send(frame []byte) {
+	select {
+	case <-c.frames:
+	default:
+	}
+
+	c.frames <- frame
+}
+
+func (c *client) writeLoop() {
+	for frame := range c.frames {
+		c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := c.conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+			c.conn.Close()
+			return
+		}
+	}
+
+	c.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(writeTimeout),
+	)
+
+	c.conn.Close()
+}
+
+// readLoop discards the messages from the browser until the connection is
+// closed
+func (c *client) readLoop() {
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func (c *client) writeJSON(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return c.conn.WriteMessage(websocket.TextMessage, b)
+}
+
+// close ends the writeLoop, closing the connection
+func (c *client) close() {
+	c.once.Do(func() { close(c.frames) })
+}
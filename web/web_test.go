@@ -0,0 +1,91 @@
+package web
+
+import (
+	"encoding/json"
+	"image/color"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type WebSuite struct{}
+
+var _ = Suite(&WebSuite{})
+
+func dial(c *C, url string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(url, "http")+"ws", nil)
+	c.Assert(err, IsNil)
+	return conn
+}
+
+func (s *WebSuite) TestPage(c *C) {
+	srv := httptest.NewServer(NewServer(2, 1))
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL)
+	c.Assert(err, IsNil)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	c.Assert(err, IsNil)
+	c.Assert(res.Header.Get("Content-Type"), Equals, "text/html; charset=utf-8")
+	c.Assert(strings.Contains(string(body), "<canvas"), Equals, true)
+
+	res, err = http.Get(srv.URL + "/foo")
+	c.Assert(err, IsNil)
+	res.Body.Close()
+	c.Assert(res.StatusCode, Equals, http.StatusNotFound)
+}
+
+func (s *WebSuite) TestFrames(c *C) {
+	m := NewServer(2, 1)
+	m.PixelPitch = 6
+	c.Assert(m.Listen(), IsNil)
+	defer m.Close()
+
+	conn := dial(c, m.URL())
+	defer conn.Close()
+
+	typ, msg, err := conn.ReadMessage()
+	c.Assert(err, IsNil)
+	c.Assert(typ, Equals, websocket.TextMessage)
+
+	var cfg config
+	c.Assert(json.Unmarshal(msg, &cfg), IsNil)
+	c.Assert(cfg, Equals, config{Width: 2, Height: 1, PixelPitch: 6})
+
+	// the last frame rendered is sent on connect
+	typ, msg, err = conn.ReadMessage()
+	c.Assert(err, IsNil)
+	c.Assert(typ, Equals, websocket.BinaryMessage)
+	c.Assert(msg, DeepEquals, []byte{0, 0, 0, 0, 0, 0})
+
+	c.Assert(m.Apply([]color.Color{color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}}), IsNil)
+	_, msg, err = conn.ReadMessage()
+	c.Assert(err, IsNil)
+	c.Assert(msg, DeepEquals, []byte{255, 0, 0, 0, 0, 255})
+	c.Assert(m.At(0), Equals, color.Black)
+
+	c.Assert(m.Close(), IsNil)
+	_, _, err = conn.ReadMessage()
+	c.Assert(err, NotNil)
+	c.Assert(m.URL(), Equals, "")
+}
+
+func (s *WebSuite) TestBackend(c *C) {
+	m, err := rgbmatrix.NewMatrix("web://127.0.0.1:0?pitch=4", nil)
+	c.Assert(err, IsNil)
+	defer m.Close()
+
+	srv := m.(*Server)
+	c.Assert(srv.PixelPitch, Equals, 4)
+	c.Assert(srv.URL(), Matches, "http://127.0.0.1:.*/")
+}
@@ -0,0 +1,302 @@
+package widgets
+
+import (
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix/internal/raster"
+)
+
+// ProgressBar renders a horizontal bar filled with the last value received,
+// the values are in the range [0, 1]. Ends when Values is closed.
+type ProgressBar struct {
+	Style
+	// Size of the frames
+	Size image.Point
+	// Border draws a border of the foreground color around the bar
+	Border bool
+
+	feed
+	m     sync.Mutex
+	value float64
+}
+
+// NewProgressBar returns a new ProgressBar of the given size fed by values
+func NewProgressBar(size image.Point, values <-chan float64) *ProgressBar {
+	return &ProgressBar{Size: size, Border: true, feed: feed{values: values}}
+}
+
+// Next returns a frame with the last value, the frame is shown until a new
+// value is received
+func (p *ProgressBar) Next() (image.Image, <-chan time.Time, error) {
+	if p.end() {
+		return nil, nil, io.EOF
+	}
+
+	p.m.Lock()
+	v := clamp(p.value, 0, 1)
+	p.m.Unlock()
+
+	frame := p.newFrame(p.Size)
+	r := frame.Bounds()
+	if p.Border {
+		rect(frame, r, p.color())
+		r = r.Inset(1)
+	}
+
+	r.Max.X = r.Min.X + int(math.Floor(float64(r.Dx())*v+.5))
+	fill(frame, r, p.color())
+
+	return frame, p.wait(p.set), nil
+}
+
+func (p *ProgressBar) set(v float64) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.value = v
+}
+
+// Gauge renders a semicircular gauge with the last value received, the values
+// are in the range [Min, Max]. Ends when Values is closed.
+type Gauge struct {
+	Style
+	// Size of the frames, the gauge is centered at the bottom of the frame
+	Size image.Point
+	// Min and Max are the range of the values
+	Min, Max float64
+	// Thickness of the arc in pixels, a quarter of the radius if zero
+	Thickness int
+	// TrackColor is the color of the empty part of the arc, gray if nil
+	TrackColor color.Color
+
+	feed
+	m     sync.Mutex
+	value float64
+}
+
+// NewGauge returns a new Gauge of the given size, for values in the range
+// [min, max], fed by values
+func NewGauge(size image.Point, min, max float64, values <-chan float64) *Gauge {
+	return &Gauge{Size: size, Min: min, Max: max, feed: feed{values: values}}
+}
+
+// Next returns a frame with the last value, the frame is shown until a new
+// value is received
+func (g *Gauge) Next() (image.Image, <-chan time.Time, error) {
+	if g.end() {
+		return nil, nil, io.EOF
+	}
+
+	g.m.Lock()
+	v := g.value
+	g.m.Unlock()
+
+	if g.Max > g.Min {
+		v = clamp((v-g.Min)/(g.Max-g.Min), 0, 1)
+	} else {
+		v = 0
+	}
+
+	track := g.TrackColor
+	if track == nil {
+		track = color.Gray{Y: 48}
+	}
+
+	frame := g.newFrame(g.Size)
+	cx, cy := float64(g.Size.X)/2, float64(g.Size.Y)
+	r := math.Min(cx, cy)
+
+	thickness := float64(g.Thickness)
+	if thickness <= 0 {
+		thickness = math.Max(1, math.Floor(r/4))
+	}
+
+	for y := 0; y < g.Size.Y; y++ {
+		for x := 0; x < g.Size.X; x++ {
+			dx, dy := float64(x)+.5-cx, cy-float64(y)-.5
+			d := math.Hypot(dx, dy)
+			if d > r || d < r-thickness {
+				continue
+			}
+
+			// angle from the left end of the arc, from 0 to 1
+			if math.Atan2(dy, -dx)/math.Pi <= v {
+				frame.Set(x, y, g.color())
+			} else {
+				frame.Set(x, y, track)
+			}
+		}
+	}
+
+	return frame, g.wait(g.set), nil
+}
+
+func (g *Gauge) set(v float64) {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	g.value = v
+}
+
+// Sparkline renders a line chart with the last values received, one value per
+// column, scaled to the min and max of the values shown. Ends when Values is
+// closed.
+type Sparkline struct {
+	Style
+	// Size of the frames
+	Size image.Point
+
+	feed
+	history
+}
+
+// NewSparkline returns a new Sparkline of the given size fed by values
+func NewSparkline(size image.Point, values <-chan float64) *Sparkline {
+	return &Sparkline{Size: size, feed: feed{values: values}}
+}
+
+// Next returns a frame with the last values, the frame is shown until a new
+// value is received
+func (s *Sparkline) Next() (image.Image, <-chan time.Time, error) {
+	if s.end() {
+		return nil, nil, io.EOF
+	}
+
+	frame := s.newFrame(s.Size)
+	values := s.last(s.Size.X)
+	min, max := bounds(values, 0, 0)
+
+	var prev image.Point
+	for i, v := range values {
+		p := image.Pt(s.Size.X-len(values)+i, scale(v, min, max, s.Size.Y))
+		if i == 0 {
+			prev = p
+		}
+
+		raster.Line(frame, prev, p, s.color())
+		prev = p
+	}
+
+	return frame, s.wait(s.add), nil
+}
+
+// BarChart renders a bar for every one of the last values received, scaled to
+// the range [Min, Max] or, if both are zero, to the min and max of the values
+// shown. Ends when Values is closed.
+type BarChart struct {
+	Style
+	// Size of the frames
+	Size image.Point
+	// BarWidth is the width of every bar, 1 if zero
+	BarWidth int
+	// Gap is the space between bars
+	Gap int
+	// Min and Max are the range of the values
+	Min, Max float64
+
+	feed
+	history
+}
+
+// NewBarChart returns a new BarChart of the given size fed by values
+func NewBarChart(size image.Point, values <-chan float64) *BarChart {
+	return &BarChart{Size: size, BarWidth: 2, Gap: 1, feed: feed{values: values}}
+}
+
+// Next returns a frame with the last values, the frame is shown until a new
+// value is received
+func (b *BarChart) Next() (image.Image, <-chan time.Time, error) {
+	if b.end() {
+		return nil, nil, io.EOF
+	}
+
+	width := b.BarWidth
+	if width <= 0 {
+		width = 1
+	}
+
+	frame := b.newFrame(b.Size)
+	values := b.last((b.Size.X + b.Gap) / (width + b.Gap))
+	min, max := bounds(values, b.Min, b.Max)
+	if min > 0 {
+		min = 0
+	}
+
+	x := b.Size.X - len(values)*(width+b.Gap) + b.Gap
+	for _, v := range values {
+		top := scale(v, min, max, b.Size.Y)
+		fill(frame, image.Rect(x, top, x+width, b.Size.Y), b.color())
+		x += width + b.Gap
+	}
+
+	return frame, b.wait(b.add), nil
+}
+
+// history keeps the last values received by a chart
+type history struct {
+	m      sync.Mutex
+	values []float64
+}
+
+// maxHistory is the max number of values kept
+const maxHistory = 1024
+
+func (h *history) add(v float64) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	h.values = append(h.values, v)
+	if len(h.values) > maxHistory {
+		h.values = h.values[len(h.values)-maxHistory:]
+	}
+}
+
+// last returns a copy of the last n values
+func (h *history) last(n int) []float64 {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if n > len(h.values) {
+		n = len(h.values)
+	}
+
+	if n < 0 {
+		n = 0
+	}
+
+	return append([]float64(nil), h.values[len(h.values)-n:]...)
+}
+
+// bounds returns min and max if min != max, or the min and max of values
+func bounds(values []float64, min, max float64) (float64, float64) {
+	if min != max || len(values) == 0 {
+		return min, max
+	}
+
+	min, max = values[0], values[0]
+	for _, v := range values {
+		min, max = math.Min(min, v), math.Max(max, v)
+	}
+
+	return min, max
+}
+
+// scale returns the row of v, in the range [min, max], in a chart of the
+// given height, the max is at the top
+func scale(v, min, max float64, height int) int {
+	if max <= min {
+		return height - 1
+	}
+
+	f := clamp((v-min)/(max-min), 0, 1)
+	return height - 1 - int(math.Floor(f*float64(height-1)+.5))
+}
+
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
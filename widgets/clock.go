@@ -0,0 +1,134 @@
+package widgets
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+	"github.com/mcuadros/go-rpi-rgb-led-matrix/internal/raster"
+)
+
+// DefaultClockFormat is the layout used by DigitalClock when no Format is given
+const DefaultClockFormat = "15:04"
+
+// DigitalClock renders the current time as text
+type DigitalClock struct {
+	Style
+	// Size of the frames
+	Size image.Point
+	// Location of the time shown, time.Local if nil
+	Location *time.Location
+	// Format is the layout of the time, as in time.Format, DefaultClockFormat
+	// if empty
+	Format string
+
+	rgbmatrix.Clocked
+}
+
+// NewDigitalClock returns a new DigitalClock of the given size showing the
+// time at the given location
+func NewDigitalClock(size image.Point, loc *time.Location) *DigitalClock {
+	return &DigitalClock{Size: size, Location: loc}
+}
+
+// Next returns a frame with the current time, the frame is shown until the
+// next second
+func (c *DigitalClock) Next() (image.Image, <-chan time.Time, error) {
+	now := c.Clock().Now()
+
+	format := c.Format
+	if format == "" {
+		format = DefaultClockFormat
+	}
+
+	text := now.In(location(c.Location)).Format(format)
+	frame := c.newFrame(c.Size)
+	x := (c.Size.X - rgbmatrix.MeasureText(text, c.face())) / 2
+	rgbmatrix.DrawText(frame, text, c.face(), c.color(), x)
+
+	return frame, untilNextSecond(c.Clock(), now), nil
+}
+
+// AnalogClock renders the current time as a clock with hands
+type AnalogClock struct {
+	Style
+	// Size of the frames, the clock is centered in the frame
+	Size image.Point
+	// Location of the time shown, time.Local if nil
+	Location *time.Location
+	// Seconds shows the second hand
+	Seconds bool
+	// SecondColor is the color of the second hand, red if nil
+	SecondColor color.Color
+	// MarksColor is the color of the hour marks, gray if nil
+	MarksColor color.Color
+
+	rgbmatrix.Clocked
+}
+
+// NewAnalogClock returns a new AnalogClock of the given size showing the
+// time at the given location
+func NewAnalogClock(size image.Point, loc *time.Location) *AnalogClock {
+	return &AnalogClock{Size: size, Location: loc, Seconds: true}
+}
+
+// Next returns a frame with the current time, the frame is shown until the
+// next second
+func (c *AnalogClock) Next() (image.Image, <-chan time.Time, error) {
+	now := c.Clock().Now()
+	t := now.In(location(c.Location))
+
+	frame := c.newFrame(c.Size)
+	center := image.Pt((c.Size.X-1)/2, (c.Size.Y-1)/2)
+	r := float64(c.Size.X-1) / 2
+	if ry := float64(c.Size.Y-1) / 2; ry < r {
+		r = ry
+	}
+
+	marks := c.MarksColor
+	if marks == nil {
+		marks = color.Gray{Y: 96}
+	}
+
+	for h := 0; h < 12; h++ {
+		p := hand(center, float64(h)/12, r)
+		frame.Set(p.X, p.Y, marks)
+	}
+
+	minutes := float64(t.Minute()) + float64(t.Second())/60
+	hours := float64(t.Hour()%12) + minutes/60
+
+	raster.Line(frame, center, hand(center, hours/12, r*.5), c.color())
+	raster.Line(frame, center, hand(center, minutes/60, r*.8), c.color())
+
+	if c.Seconds {
+		second := c.SecondColor
+		if second == nil {
+			second = color.RGBA{255, 0, 0, 255}
+		}
+
+		raster.Line(frame, center, hand(center, float64(t.Second())/60, r*.9), second)
+	}
+
+	return frame, untilNextSecond(c.Clock(), now), nil
+}
+
+// hand returns the end of a hand of the given length, pointing to the given
+// fraction of a turn, clockwise starting at 12 o'clock
+func hand(center image.Point, turn, length float64) image.Point {
+	a := turn * 2 * math.Pi
+	return image.Pt(
+		center.X+int(math.Floor(math.Sin(a)*length+.5)),
+		center.Y-int(math.Floor(math.Cos(a)*length+.5)),
+	)
+}
+
+func location(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.Local
+	}
+
+	return loc
+}
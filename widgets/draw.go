@@ -0,0 +1,26 @@
+package widgets
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix/internal/raster"
+)
+
+// rect draws the outline of r
+func rect(dst draw.Image, r image.Rectangle, c color.Color) {
+	if r.Empty() {
+		return
+	}
+
+	raster.Line(dst, r.Min, image.Pt(r.Max.X-1, r.Min.Y), c)
+	raster.Line(dst, image.Pt(r.Min.X, r.Max.Y-1), r.Max.Sub(image.Pt(1, 1)), c)
+	raster.Line(dst, r.Min, image.Pt(r.Min.X, r.Max.Y-1), c)
+	raster.Line(dst, image.Pt(r.Max.X-1, r.Min.Y), r.Max.Sub(image.Pt(1, 1)), c)
+}
+
+// fill fills r with c
+func fill(dst draw.Image, r image.Rectangle, c color.Color) {
+	draw.Draw(dst, r, &image.Uniform{c}, image.ZP, draw.Src)
+}
@@ -0,0 +1,101 @@
+package widgets
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"time"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+)
+
+// Countdown renders the time remaining until a given time, once the time is
+// reached a frame with zero is shown during a second and io.EOF is returned
+type Countdown struct {
+	Style
+	// Size of the frames
+	Size image.Point
+	// Until is the end of the countdown
+	Until time.Time
+
+	rgbmatrix.Clocked
+	done bool
+}
+
+// NewCountdown returns a new Countdown of the given size until the given time
+func NewCountdown(size image.Point, until time.Time) *Countdown {
+	return &Countdown{Size: size, Until: until}
+}
+
+// Next returns a frame with the remaining time
+func (c *Countdown) Next() (image.Image, <-chan time.Time, error) {
+	if c.done {
+		return nil, nil, io.EOF
+	}
+
+	now := c.Clock().Now()
+	remaining := c.Until.Sub(now)
+	if remaining <= 0 {
+		c.done = true
+		return c.render(0), c.Clock().After(time.Second), nil
+	}
+
+	// the remaining time is rounded up, so zero is only shown at the end
+	next := remaining - remaining.Truncate(time.Second)
+	if next == 0 {
+		next = time.Second
+	}
+
+	return c.render(remaining + time.Second - next), c.Clock().After(next), nil
+}
+
+func (c *Countdown) render(d time.Duration) image.Image {
+	frame := c.newFrame(c.Size)
+	text := formatDuration(d)
+	x := (c.Size.X - rgbmatrix.MeasureText(text, c.face())) / 2
+	rgbmatrix.DrawText(frame, text, c.face(), c.color(), x)
+
+	return frame
+}
+
+// CountUp renders the time elapsed since a given time, it never ends
+type CountUp struct {
+	Style
+	// Size of the frames
+	Size image.Point
+	// Since is the start of the count
+	Since time.Time
+
+	rgbmatrix.Clocked
+}
+
+// NewCountUp returns a new CountUp of the given size since the given time
+func NewCountUp(size image.Point, since time.Time) *CountUp {
+	return &CountUp{Size: size, Since: since}
+}
+
+// Next returns a frame with the elapsed time
+func (c *CountUp) Next() (image.Image, <-chan time.Time, error) {
+	now := c.Clock().Now()
+	elapsed := now.Sub(c.Since)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	frame := c.newFrame(c.Size)
+	text := formatDuration(elapsed)
+	x := (c.Size.X - rgbmatrix.MeasureText(text, c.face())) / 2
+	rgbmatrix.DrawText(frame, text, c.face(), c.color(), x)
+
+	return frame, c.Clock().After(time.Second - elapsed%time.Second), nil
+}
+
+// formatDuration formats d as MM:SS, or H:MM:SS if longer than an hour
+func formatDuration(d time.Duration) string {
+	s := int(d / time.Second)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+
+	return fmt.Sprintf("%02d:%02d", s/60, s%60)
+}
@@ -0,0 +1,131 @@
+// Package widgets contains ready to use Animations, like clocks, timers,
+// progress bars or charts, rendering frames of a given size, usually the size
+// of a SubCanvas or a Compositor layer.
+package widgets
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-rpi-rgb-led-matrix"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+// Style defines the look of a widget
+type Style struct {
+	// Face used to draw texts, basicfont.Face7x13 if nil
+	Face font.Face
+	// Color of the foreground, white if nil
+	Color color.Color
+	// Background color, black if nil
+	Background color.Color
+}
+
+func (s *Style) face() font.Face {
+	if s.Face == nil {
+		return basicfont.Face7x13
+	}
+
+	return s.Face
+}
+
+func (s *Style) color() color.Color {
+	if s.Color == nil {
+		return color.White
+	}
+
+	return s.Color
+}
+
+// newFrame returns a new frame of the given size filled with the background
+func (s *Style) newFrame(size image.Point) *image.RGBA {
+	bg := s.Background
+	if bg == nil {
+		bg = color.Black
+	}
+
+	frame := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	draw.Draw(frame, frame.Bounds(), &image.Uniform{bg}, image.ZP, draw.Src)
+	return frame
+}
+
+// feed receives the values rendered by a widget from a chan
+type feed struct {
+	rgbmatrix.Clocked
+	values <-chan float64
+
+	once    sync.Once
+	changed chan time.Time
+	m       sync.Mutex
+	closed  bool
+	// ended is true once the frame drawn after values was closed is returned
+	ended bool
+}
+
+// wait returns a chan receiving a value once a new value is received and
+// passed to update, or once values is closed. The values are read by a single
+// goroutine, started by the first call.
+func (f *feed) wait(update func(v float64)) <-chan time.Time {
+	f.once.Do(func() {
+		f.changed = make(chan time.Time, 1)
+		go f.read(update)
+	})
+
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.ended {
+		// the frame with the last value is followed by the end at once
+		c := make(chan time.Time, 1)
+		c <- f.Clock().Now()
+		return c
+	}
+
+	return f.changed
+}
+
+// read passes every value received to update, until values is closed
+func (f *feed) read(update func(v float64)) {
+	for v := range f.values {
+		update(v)
+		f.notify()
+	}
+
+	f.m.Lock()
+	f.closed = true
+	f.m.Unlock()
+
+	f.notify()
+}
+
+// notify sends the time to changed, unless a previous one wasn't received yet
+func (f *feed) notify() {
+	select {
+	case f.changed <- f.Clock().Now():
+	default:
+	}
+}
+
+// end returns true if the widget has ended, the frame drawn once values is
+// closed is returned first, so the last value received is always rendered
+func (f *feed) end() bool {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.ended {
+		return true
+	}
+
+	f.ended = f.closed
+	return false
+}
+
+// untilNextSecond returns a chan receiving a value at the beginning of the
+// next second of the given time
+func untilNextSecond(clock rgbmatrix.Clock, t time.Time) <-chan time.Time {
+	return clock.After(t.Truncate(time.Second).Add(time.Second).Sub(t))
+}
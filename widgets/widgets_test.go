@@ -0,0 +1,124 @@
+package widgets
+
+import (
+	"image"
+	"image/color"
+	"io"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type WidgetsSuite struct{}
+
+var _ = Suite(&WidgetsSuite{})
+
+var white = color.RGBAModel.Convert(color.White)
+
+func (s *WidgetsSuite) TestFormatDuration(c *C) {
+	c.Assert(formatDuration(0), Equals, "00:00")
+	c.Assert(formatDuration(90*time.Second), Equals, "01:30")
+	c.Assert(formatDuration(3723*time.Second), Equals, "1:02:03")
+}
+
+func (s *WidgetsSuite) TestProgressBar(c *C) {
+	values := make(chan float64, 1)
+	p := NewProgressBar(image.Pt(12, 4), values)
+
+	img, next, err := p.Next()
+	c.Assert(err, IsNil)
+	c.Assert(img.At(0, 0), Equals, white)
+	c.Assert(img.At(1, 1), Equals, color.RGBAModel.Convert(color.Black))
+
+	values <- .5
+	<-next
+
+	img, next, err = p.Next()
+	c.Assert(err, IsNil)
+	c.Assert(img.At(5, 1), Equals, white)
+	c.Assert(img.At(6, 1), Equals, color.RGBAModel.Convert(color.Black))
+
+	values <- 1
+	close(values)
+
+	// the last value is drawn before the end
+	var last image.Image
+	for {
+		<-next
+		img, next, err = p.Next()
+		if err == io.EOF {
+			break
+		}
+
+		c.Assert(err, IsNil)
+		last = img
+	}
+
+	c.Assert(last.At(10, 1), Equals, white)
+}
+
+func (s *WidgetsSuite) TestBarChart(c *C) {
+	values := make(chan float64, 1)
+	b := NewBarChart(image.Pt(5, 4), values)
+	b.Max = 3
+
+	_, next, _ := b.Next()
+	for _, v := range []float64{1, 3} {
+		values <- v
+		<-next
+		_, next, _ = b.Next()
+	}
+
+	values <- 2
+	<-next
+
+	img, _, err := b.Next()
+	c.Assert(err, IsNil)
+	c.Assert(img.At(1, 0), Equals, white)
+	c.Assert(img.At(2, 1), Equals, color.RGBAModel.Convert(color.Black))
+	c.Assert(img.At(3, 0), Equals, color.RGBAModel.Convert(color.Black))
+	c.Assert(img.At(4, 1), Equals, white)
+}
+
+func (s *WidgetsSuite) TestCountdown(c *C) {
+	cd := NewCountdown(image.Pt(40, 13), time.Now().Add(-time.Second))
+
+	img, _, err := cd.Next()
+	c.Assert(err, IsNil)
+	c.Assert(img.Bounds().Size(), Equals, image.Pt(40, 13))
+
+	_, _, err = cd.Next()
+	c.Assert(err, Equals, io.EOF)
+}
+
+func (s *WidgetsSuite) TestProgressBarSingleReader(c *C) {
+	values := make(chan float64)
+	p := NewProgressBar(image.Pt(12, 4), values)
+
+	_, first, err := p.Next()
+	c.Assert(err, IsNil)
+	_, next, err := p.Next()
+	c.Assert(err, IsNil)
+	c.Assert(next, Equals, first)
+
+	values <- .5
+	<-next
+
+	img, next, err := p.Next()
+	c.Assert(err, IsNil)
+	c.Assert(img.At(5, 1), Equals, white)
+
+	close(values)
+	<-next
+
+	img, next, err = p.Next()
+	c.Assert(err, IsNil)
+	c.Assert(img.At(5, 1), Equals, white)
+	<-next
+
+	_, _, err = p.Next()
+	c.Assert(err, Equals, io.EOF)
+}